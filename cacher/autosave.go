@@ -2,8 +2,6 @@ package cacher
 
 import (
 	"time"
-
-	"github.com/winezer0/xutils/logging"
 )
 
 // scheduleAutoSave 在数据变更后延迟触发一次自动保存。
@@ -62,6 +60,6 @@ func (m *CacheManager) runAutoSave() {
 		return
 	}
 	if err := m.SaveCache(); err != nil {
-		logging.Warnf("save cache error: %v", err)
+		m.logger().Warnf("save cache error: %v", err)
 	}
 }
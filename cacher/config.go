@@ -3,7 +3,7 @@ package cacher
 import (
 	"time"
 
-	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/liblog"
 )
 
 const (
@@ -19,6 +19,7 @@ type Config struct {
 	MaxEntries      int
 	MaxDataBytes    int64
 	DisableAutoSave bool
+	Logger          liblog.Logger // 内部日志输出，为空时使用 SetLogger 设置的包级日志器
 }
 
 // NewCacheManager 使用默认配置创建缓存管理器。
@@ -56,13 +57,14 @@ func NewCacheManagerWithConfig(cfg Config) *CacheManager {
 		maxEntries:      cfg.MaxEntries,
 		maxDataBytes:    cfg.MaxDataBytes,
 		disableAutoSave: cfg.DisableAutoSave,
+		logger:          cfg.Logger,
 	}
 	manager := &CacheManager{state: state}
 	if cfg.CacheFile == "" {
 		return manager
 	}
 	if err := manager.LoadCache(); err != nil {
-		logging.Warnf("load cache file error: %v", err)
+		manager.logger().Warnf("load cache file error: %v", err)
 	}
 	return manager
 }
@@ -0,0 +1,11 @@
+package cacher
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger 未在 Config.Logger 中指定日志器的缓存管理器共用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置缓存加载与自动保存失败时的警告输出，Config.Logger 为空的缓存管理器使用该日志器，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
@@ -5,6 +5,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/winezer0/xutils/liblog"
 )
 
 // CacheManager 表示单个缓存文件对应的管理器。
@@ -30,6 +32,7 @@ type cacheManagerState struct {
 	autoSaveTimer *time.Timer
 
 	disableAutoSave bool
+	logger          liblog.Logger
 	closed          bool
 	closeOnce       sync.Once
 }
@@ -51,6 +54,15 @@ func (m *CacheManager) getState() *cacheManagerState {
 	return m.state
 }
 
+// logger 获取缓存管理器使用的日志器，未单独设置时使用包级日志器。
+func (m *CacheManager) logger() liblog.Logger {
+	state := m.getState()
+	if state == nil || state.logger == nil {
+		return pkgLogger.Get()
+	}
+	return state.logger
+}
+
 // getCacheFileLock 获取指定缓存文件路径对应的互斥锁。
 func getCacheFileLock(cacheFile string) *sync.Mutex {
 	if cacheFile == "" {
@@ -0,0 +1,114 @@
+package casstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// GC 删除不再被任何逻辑键引用的内容块和残留的临时文件，并压缩索引文件。
+// GC 期间 Put 会被阻塞。
+func (s *Store) GC() (GCResult, error) {
+	s.gcMu.Lock()
+	defer s.gcMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return GCResult{}, ErrStoreClosed
+	}
+
+	referenced := make(map[string]struct{}, len(s.index))
+	for _, digest := range s.index {
+		referenced[digest] = struct{}{}
+	}
+
+	var result GCResult
+	var errList []error
+	walkErr := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errList = append(errList, err)
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !isBlobCandidate(s.dir, path) {
+			return nil
+		}
+		name := d.Name()
+		if _, ok := referenced[name]; ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			errList = append(errList, err)
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			errList = append(errList, err)
+			return nil
+		}
+		result.RemovedBlobs++
+		result.FreedBytes += info.Size()
+		return nil
+	})
+	if walkErr != nil {
+		errList = append(errList, walkErr)
+	}
+	if err := s.compactIndex(); err != nil {
+		errList = append(errList, err)
+	}
+	return result, errors.Join(errList...)
+}
+
+// isBlobCandidate 判断路径是否位于 ab/cd/ 两级分片目录下，只有这类文件才会被回收。
+func isBlobCandidate(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	return len(parts) == 3 && len(parts[0]) == 2 && len(parts[1]) == 2
+}
+
+// compactIndex 用当前映射重写索引文件，去掉被覆盖和已删除的历史记录，调用方需持有 mu。
+func (s *Store) compactIndex() error {
+	var builder strings.Builder
+	for key, digest := range s.index {
+		line, err := json.Marshal(indexRecord{Key: key, Digest: digest})
+		if err != nil {
+			return err
+		}
+		builder.Write(line)
+		builder.WriteByte('\n')
+	}
+
+	// 先写好并打开新索引文件，替换成功后再关闭旧句柄，任何一步失败都继续使用原索引文件
+	tmpPath := s.indexPath + ".compact"
+	if err := utils.WriteBytesAtomic(tmpPath, []byte(builder.String())); err != nil {
+		return fmt.Errorf("compact cas index error: %w", err)
+	}
+	f, err := os.OpenFile(tmpPath, utils.ParseFlagFromMode("a"), 0644)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("open compacted cas index error: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.indexPath); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("replace cas index error: %w", err)
+	}
+	old := s.indexFile
+	s.indexFile = f
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("close old cas index error: %w", err)
+	}
+	return nil
+}
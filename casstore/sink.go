@@ -0,0 +1,24 @@
+package casstore
+
+import "github.com/winezer0/xutils/poolwriter"
+
+// Sink 将写盘任务保存到内容寻址存储，StorePath 作为逻辑键，可直接用于 poolwriter.NewSinkTask。
+type Sink struct {
+	store *Store
+}
+
+// NewSink 创建基于 Store 的 poolwriter 写入目标。
+func NewSink(store *Store) *Sink {
+	return &Sink{store: store}
+}
+
+// WriteTask 保存任务内容，重复内容只保留一份。
+func (s *Sink) WriteTask(task poolwriter.StoreTask) error {
+	_, err := s.store.Put(task.StorePath, task.StoreContent)
+	return err
+}
+
+// Close 存储由调用方负责关闭，这里不做处理。
+func (s *Sink) Close() error {
+	return nil
+}
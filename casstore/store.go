@@ -0,0 +1,254 @@
+package casstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/winezer0/xutils/hashutils"
+	"github.com/winezer0/xutils/utils"
+)
+
+const defaultIndexName = "index.jsonl"
+
+var (
+	ErrKeyNotFound = errors.New("cas key not found")
+	ErrStoreClosed = errors.New("cas store closed")
+)
+
+// Store 表示按 SHA-256 内容寻址的存储，相同内容只落盘一次。
+// 内容块按 ab/cd/abcd… 分片保存，逻辑键与摘要的对应关系以 JSON 行追加到索引文件，打开时回放，GC 时压缩。
+type Store struct {
+	dir       string
+	indexPath string
+
+	gcMu      sync.RWMutex // Put 持读锁、GC 持写锁，避免刚写入尚未登记的内容块被回收
+	mu        sync.RWMutex
+	index     map[string]string
+	indexFile *os.File
+	closed    bool
+}
+
+// indexRecord 表示索引文件中的一行记录，Digest 为空表示删除该键。
+type indexRecord struct {
+	Key    string `json:"k"`
+	Digest string `json:"d"`
+}
+
+// GCResult 表示一次垃圾回收的结果。
+type GCResult struct {
+	RemovedBlobs int   // 删除的内容块数量
+	FreedBytes   int64 // 释放的字节数
+}
+
+// Open 打开或创建内容寻址存储，索引文件位于 dir/index.jsonl。
+func Open(dir string) (*Store, error) {
+	return OpenWithIndex(dir, filepath.Join(dir, defaultIndexName))
+}
+
+// OpenWithIndex 打开或创建内容寻址存储，并使用指定的索引文件。
+func OpenWithIndex(dir string, indexPath string) (*Store, error) {
+	if err := utils.EnsureDir(dir, false); err != nil {
+		return nil, fmt.Errorf("create cas dir error: %w", err)
+	}
+	s := &Store{
+		dir:       dir,
+		indexPath: indexPath,
+		index:     make(map[string]string),
+	}
+	if err := s.loadIndex(); err != nil {
+		return nil, err
+	}
+	if err := s.openIndexFile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Put 保存内容并将逻辑键指向其摘要，返回内容摘要。
+func (s *Store) Put(key string, data []byte) (string, error) {
+	s.gcMu.RLock()
+	defer s.gcMu.RUnlock()
+	if s.isClosed() {
+		return "", ErrStoreClosed
+	}
+
+	digest, err := s.putBlob(data)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return "", ErrStoreClosed
+	}
+	if s.index[key] == digest {
+		return digest, nil
+	}
+	if err := s.appendIndex(indexRecord{Key: key, Digest: digest}); err != nil {
+		return "", err
+	}
+	s.index[key] = digest
+	return digest, nil
+}
+
+// Get 读取逻辑键对应的内容。
+func (s *Store) Get(key string) ([]byte, error) {
+	digest, ok := s.Digest(key)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return s.GetBlob(digest)
+}
+
+// GetBlob 读取摘要对应的内容块。
+func (s *Store) GetBlob(digest string) ([]byte, error) {
+	return os.ReadFile(s.BlobPath(digest))
+}
+
+// Exists 判断逻辑键是否存在。
+func (s *Store) Exists(key string) bool {
+	_, ok := s.Digest(key)
+	return ok
+}
+
+// HasBlob 判断摘要对应的内容块是否已落盘。
+func (s *Store) HasBlob(digest string) bool {
+	return utils.FileExists(s.BlobPath(digest))
+}
+
+// Digest 获取逻辑键对应的摘要。
+func (s *Store) Digest(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	digest, ok := s.index[key]
+	return digest, ok
+}
+
+// Delete 删除逻辑键，内容块在下一次 GC 时回收。
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrStoreClosed
+	}
+	if _, ok := s.index[key]; !ok {
+		return nil
+	}
+	if err := s.appendIndex(indexRecord{Key: key}); err != nil {
+		return err
+	}
+	delete(s.index, key)
+	return nil
+}
+
+// Len 返回逻辑键数量。
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.index)
+}
+
+// BlobPath 返回摘要对应的内容块路径。
+func (s *Store) BlobPath(digest string) string {
+	if len(digest) < 4 {
+		return filepath.Join(s.dir, digest)
+	}
+	return filepath.Join(s.dir, digest[:2], digest[2:4], digest)
+}
+
+// isClosed 判断存储是否已关闭。
+func (s *Store) isClosed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closed
+}
+
+// Close 关闭索引文件。
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.indexFile.Close()
+}
+
+// putBlob 写入内容块，已存在时直接复用。
+func (s *Store) putBlob(data []byte) (string, error) {
+	digest := hashutils.GetBytesSHA256(data)
+	blobPath := s.BlobPath(digest)
+	if utils.FileExists(blobPath) {
+		return digest, nil
+	}
+	if err := utils.WriteBytesAtomic(blobPath, data); err != nil {
+		return "", fmt.Errorf("write cas blob error: %w", err)
+	}
+	return digest, nil
+}
+
+// loadIndex 回放索引文件，重建逻辑键与摘要的对应关系。
+func (s *Store) loadIndex() error {
+	f, err := os.Open(s.indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("open cas index error: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record indexRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("parse cas index line %d error: %w", lineNum, err)
+		}
+		if record.Digest == "" {
+			delete(s.index, record.Key)
+		} else {
+			s.index[record.Key] = record.Digest
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read cas index error: %w", err)
+	}
+	return nil
+}
+
+// openIndexFile 以追加模式打开索引文件。
+func (s *Store) openIndexFile() error {
+	if err := utils.EnsureDir(s.indexPath, true); err != nil {
+		return fmt.Errorf("create cas index dir error: %w", err)
+	}
+	f, err := os.OpenFile(s.indexPath, utils.ParseFlagFromMode("a"), 0644)
+	if err != nil {
+		return fmt.Errorf("open cas index error: %w", err)
+	}
+	s.indexFile = f
+	return nil
+}
+
+// appendIndex 追加一条索引记录，调用方需持有 mu。
+func (s *Store) appendIndex(record indexRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := s.indexFile.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write cas index error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package casstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/winezer0/xutils/hashutils"
+	"github.com/winezer0/xutils/poolwriter"
+)
+
+// TestStorePutGet 验证相同内容只存储一份，并按分片目录布局保存。
+func TestStorePutGet(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store failed: %v", err)
+	}
+	defer store.Close()
+
+	d1, err := store.Put("http://a/", []byte("same body"))
+	if err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	d2, _ := store.Put("http://b/", []byte("same body"))
+	if d1 != d2 || d1 != hashutils.GetBytesSHA256([]byte("same body")) {
+		t.Fatalf("unexpected digests: %s %s", d1, d2)
+	}
+	if _, err := os.Stat(filepath.Join(dir, d1[:2], d1[2:4], d1)); err != nil {
+		t.Fatalf("blob not in sharded layout: %v", err)
+	}
+
+	data, err := store.Get("http://b/")
+	if err != nil || string(data) != "same body" {
+		t.Fatalf("unexpected get result: %q, %v", string(data), err)
+	}
+	if !store.Exists("http://a/") || store.Exists("http://c/") {
+		t.Fatal("unexpected exists result")
+	}
+	if _, err := store.Get("http://c/"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got: %v", err)
+	}
+}
+
+// TestStoreReopen 验证重新打开后能从索引文件恢复映射。
+func TestStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store failed: %v", err)
+	}
+	store.Put("k1", []byte("v1"))
+	store.Put("k2", []byte("v2"))
+	store.Put("k1", []byte("v1-new"))
+	store.Delete("k2")
+	if err := store.Close(); err != nil {
+		t.Fatalf("close store failed: %v", err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen store failed: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.Len() != 1 || reopened.Exists("k2") {
+		t.Fatalf("unexpected keys after reopen: %d", reopened.Len())
+	}
+	data, err := reopened.Get("k1")
+	if err != nil || string(data) != "v1-new" {
+		t.Fatalf("unexpected value: %q, %v", string(data), err)
+	}
+}
+
+// TestStoreGC 验证 GC 回收未引用的内容块并压缩索引。
+func TestStoreGC(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store failed: %v", err)
+	}
+	defer store.Close()
+
+	keep, _ := store.Put("keep", []byte("keep body"))
+	drop, _ := store.Put("drop", []byte("drop body"))
+	store.Put("keep", []byte("keep body"))
+	store.Delete("drop")
+
+	result, err := store.GC()
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if result.RemovedBlobs != 1 || result.FreedBytes != int64(len("drop body")) {
+		t.Fatalf("unexpected gc result: %+v", result)
+	}
+	if !store.HasBlob(keep) || store.HasBlob(drop) {
+		t.Fatal("unexpected blobs after gc")
+	}
+
+	index, _ := os.ReadFile(filepath.Join(dir, defaultIndexName))
+	if want := `{"k":"keep","d":"` + keep + `"}` + "\n"; string(index) != want {
+		t.Fatalf("unexpected compacted index: %q", string(index))
+	}
+
+	// 压缩后仍可继续追加索引
+	if _, err := store.Put("new", []byte("new body")); err != nil {
+		t.Fatalf("put after gc failed: %v", err)
+	}
+	if !store.Exists("new") {
+		t.Fatal("expected new key after gc")
+	}
+}
+
+// TestSinkWithPool 验证通过 poolwriter 写入时相同响应体只存储一份。
+func TestSinkWithPool(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store failed: %v", err)
+	}
+	defer store.Close()
+
+	sink := NewSink(store)
+	p := poolwriter.NewPool(4, 16)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		p.Submit(poolwriter.NewSinkTask(sink, key, []byte("body"), true, true))
+	}
+	p.StopAndWait()
+
+	if store.Len() != 4 || p.GetFailCount() != 0 {
+		t.Fatalf("unexpected store len %d, fail count %d", store.Len(), p.GetFailCount())
+	}
+	result, err := store.GC()
+	if err != nil || result.RemovedBlobs != 0 {
+		t.Fatalf("unexpected gc result: %+v, %v", result, err)
+	}
+}
+
+// TestStoreClosedAndCompact 验证关闭后 Put 不再写入内容块，GC 压缩索引后仍可继续写入。
+func TestStoreClosedAndCompact(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("open store failed: %v", err)
+	}
+	store.Put("a", []byte("v1"))
+	store.Put("a", []byte("v2"))
+	if _, err := store.GC(); err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if _, err := store.Put("b", []byte("v3")); err != nil {
+		t.Fatalf("put after gc failed: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if _, err := store.Put("c", []byte("closed")); !errors.Is(err, ErrStoreClosed) {
+		t.Fatalf("expected ErrStoreClosed, got: %v", err)
+	}
+	if store.HasBlob(hashutils.GetBytesSHA256([]byte("closed"))) {
+		t.Fatal("blob written after close")
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen store failed: %v", err)
+	}
+	defer reopened.Close()
+	if data, err := reopened.Get("b"); err != nil || string(data) != "v3" || reopened.Len() != 2 {
+		t.Fatalf("unexpected reopened store: %q, %v, %d keys", data, err, reopened.Len())
+	}
+}
@@ -0,0 +1,11 @@
+package cmdutils
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger 命令行参数解析使用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置解析命令行参数时的警告输出，如 ParseHeaders 跳过的无效请求头，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
@@ -1,7 +1,6 @@
 package cmdutils
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -14,7 +13,7 @@ func ParseHeaders(headers []string) map[string]string {
 		}
 		kv := strings.SplitN(item, "=", 2)
 		if len(kv) != 2 {
-			fmt.Printf("Invalid header: %s\n", item)
+			pkgLogger.Get().Warnf("invalid header: %s", item)
 			continue
 		}
 		headerMaps[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
@@ -0,0 +1,263 @@
+package csvutils
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/winezer0/xutils/cacher"
+	"github.com/winezer0/xutils/utils"
+)
+
+// Checkpoint CSV 读取检查点，记录最后一条已提交记录之后的位置，恢复时直接定位到该位置并复用表头
+type Checkpoint struct {
+	Offset    int64    `json:"offset"`    // 下一条记录的字节偏移，UTF-8 文件为文件偏移，其他编码为解码后数据流中的偏移
+	Rows      int      `json:"rows"`      // 已提交的数据记录数
+	Line      int      `json:"line"`      // 最后一条已提交记录的结束物理行号
+	Header    []string `json:"header"`    // 列名
+	Delimiter rune     `json:"delimiter"` // 分隔符
+	Encoding  string   `json:"encoding"`  // 文件编码
+	FileSize  int64    `json:"file_size"` // 保存检查点时的文件大小，用于检测文件被截断或替换
+}
+
+// CheckpointStore 检查点存储
+type CheckpointStore interface {
+	// Load 加载检查点，不存在时返回 false
+	Load() (Checkpoint, bool, error)
+	// Save 保存检查点
+	Save(cp Checkpoint) error
+	// Clear 删除检查点
+	Clear() error
+}
+
+// DefaultCheckpointPath 返回 CSV 文件默认的旁路检查点文件路径
+func DefaultCheckpointPath(filePath string) string {
+	return filePath + ".checkpoint.json"
+}
+
+// fileCheckpointStore 以 JSON 旁路文件保存检查点
+type fileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore 创建以 JSON 文件保存检查点的存储，通过 utils.WriteBytesAtomic 写入，避免中途崩溃损坏检查点
+func NewFileCheckpointStore(path string) CheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
+
+func (s *fileCheckpointStore) Load() (Checkpoint, bool, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cp, false, nil
+		}
+		return cp, false, fmt.Errorf("read checkpoint failed: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, false, fmt.Errorf("parse checkpoint %s failed: %w", s.path, err)
+	}
+	return cp, true, nil
+}
+
+func (s *fileCheckpointStore) Save(cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint failed: %w", err)
+	}
+	if err := utils.WriteBytesAtomic(s.path, data); err != nil {
+		return fmt.Errorf("write checkpoint failed: %w", err)
+	}
+	return nil
+}
+
+func (s *fileCheckpointStore) Clear() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove checkpoint failed: %w", err)
+	}
+	return nil
+}
+
+// cacheCheckpointStore 以 cacher 缓存项保存检查点
+type cacheCheckpointStore struct {
+	cache *cacher.CacheManager
+	key   string
+}
+
+// NewCacheCheckpointStore 创建保存在 cacher 缓存中的检查点存储，多个文件可共用同一个缓存文件，以 key 区分，
+// 每次保存后立即写盘
+func NewCacheCheckpointStore(cache *cacher.CacheManager, key string) CheckpointStore {
+	return &cacheCheckpointStore{cache: cache, key: key}
+}
+
+func (s *cacheCheckpointStore) Load() (Checkpoint, bool, error) {
+	var cp Checkpoint
+	ok, err := s.cache.GetAs(s.key, &cp)
+	if err != nil {
+		if errors.Is(err, cacher.ErrCacheKeyNotFound) {
+			return cp, false, nil
+		}
+		return cp, false, fmt.Errorf("load checkpoint failed: %w", err)
+	}
+	return cp, ok, nil
+}
+
+func (s *cacheCheckpointStore) Save(cp Checkpoint) error {
+	if err := s.cache.Set(s.key, cp); err != nil {
+		return fmt.Errorf("save checkpoint failed: %w", err)
+	}
+	return s.cache.SaveCache()
+}
+
+func (s *cacheCheckpointStore) Clear() error {
+	if err := s.cache.Del(s.key); err != nil && !errors.Is(err, cacher.ErrCacheKeyNotFound) {
+		return fmt.Errorf("clear checkpoint failed: %w", err)
+	}
+	return s.cache.SaveCache()
+}
+
+// Resume 使用默认旁路检查点文件恢复迭代器，没有检查点时从文件开头读取，调用 Commit 保存读取进度
+func Resume(filePath string) (*CSVIterator, error) {
+	return ResumeWithOptions(filePath, DefaultReadOptions(), NewFileCheckpointStore(DefaultCheckpointPath(filePath)))
+}
+
+// ResumeWithOptions 从 store 中的检查点恢复迭代器，表头、分隔符与编码沿用检查点中的值，不再重新读取表头。
+// 没有检查点时按读取选项从文件开头读取
+func ResumeWithOptions(filePath string, opts ReadOptions, store CheckpointStore) (*CSVIterator, error) {
+	if store == nil {
+		return nil, errors.New("checkpoint store cannot be nil")
+	}
+	cp, ok, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		iter, err := NewCSVIteratorWithOptions(filePath, opts)
+		if err != nil {
+			return nil, err
+		}
+		iter.store = store
+		return iter, nil
+	}
+
+	src, err := openCSVSourceAt(filePath, opts, cp)
+	if err != nil {
+		return nil, fmt.Errorf("resume from checkpoint failed: %w", err)
+	}
+	return &CSVIterator{src: src, header: src.Header(), store: store, rows: cp.Rows}, nil
+}
+
+// ReadBigCSVToDictsWithCheckpoint 从 store 中的检查点继续逐行读取并交给 callback 处理，
+// 每成功处理 commitEvery 条记录（<= 0 时为 1000）及读取结束时保存检查点，callback 失败的记录不会被提交。
+// 读取完成后检查点保留在最终位置，文件追加数据后再次调用只处理新增的记录，需要重新处理时调用 store.Clear
+func ReadBigCSVToDictsWithCheckpoint(
+	filePath string,
+	opts ReadOptions,
+	store CheckpointStore,
+	commitEvery int,
+	callback func(map[string]interface{}) error,
+) (ReadSummary, error) {
+	if callback == nil {
+		return ReadSummary{}, errors.New("callback function cannot be nil")
+	}
+	if commitEvery <= 0 {
+		commitEvery = 1000
+	}
+
+	iter, err := ResumeWithOptions(filePath, opts, store)
+	if err != nil {
+		return ReadSummary{}, err
+	}
+	defer iter.Close()
+
+	for dict := iter.Next(); dict != nil; dict = iter.Next() {
+		if err := callback(dict); err != nil {
+			return iter.Summary(), fmt.Errorf("callback failed at row %d: %w", iter.Line(), err)
+		}
+		if iter.rows%commitEvery == 0 {
+			if err := iter.Commit(); err != nil {
+				return iter.Summary(), err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil && !errors.Is(err, io.EOF) {
+		return iter.Summary(), err
+	}
+	return iter.Summary(), iter.Commit()
+}
+
+// openCSVSourceAt 从检查点位置打开 CSV 文件，UTF-8 文件直接定位，其他编码需解码并丢弃偏移之前的数据
+func openCSVSourceAt(filePath string, opts ReadOptions, cp Checkpoint) (*csvSource, error) {
+	opts, err := normalizeReadOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(cp.Header) == 0 || cp.Offset < 0 {
+		return nil, errors.New("invalid checkpoint")
+	}
+	file, err := openCSVFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("stat file failed: %w", err)
+	}
+	if info.Size() < cp.FileSize {
+		file.Close()
+		return nil, fmt.Errorf("file %s is smaller than when the checkpoint was saved", filePath)
+	}
+
+	opts.Encoding = cp.Encoding
+	opts.Delimiter = cp.Delimiter
+	var r io.Reader = file
+	if isUTF8Encoding(cp.Encoding) {
+		_, err = file.Seek(cp.Offset, io.SeekStart)
+	} else {
+		r = decodeReader(file, cp.Encoding)
+		_, err = io.CopyN(io.Discard, r, cp.Offset)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("seek to offset %d failed: %w", cp.Offset, err)
+	}
+
+	src := &csvSource{
+		file:     file,
+		reader:   newCSVReader(bufio.NewReaderSize(r, opts.BufferSize), opts),
+		opts:     opts,
+		header:   cp.Header,
+		base:     cp.Offset,
+		lineBase: cp.Line,
+		pos:      recordPos{line: cp.Line, endLine: cp.Line, offset: cp.Offset},
+	}
+	src.columns = resolveColumns(src.header, opts)
+	return src, nil
+}
+
+// checkpoint 生成最近读取的记录之后位置的检查点，rows 为已提交的数据记录数
+func (s *csvSource) checkpoint(rows int) Checkpoint {
+	pos := s.pos
+	if s.pending != nil {
+		// HeaderNone 预读的首行尚未交付，检查点停在其之前
+		pos = recordPos{endLine: s.lineBase, offset: s.base}
+	}
+	cp := Checkpoint{
+		Offset:    pos.offset,
+		Rows:      rows,
+		Line:      pos.endLine,
+		Header:    s.header,
+		Delimiter: s.opts.Delimiter,
+		Encoding:  s.opts.Encoding,
+	}
+	if s.file != nil {
+		if info, err := s.file.Stat(); err == nil {
+			cp.FileSize = info.Size()
+		}
+	}
+	return cp
+}
@@ -0,0 +1,98 @@
+package csvutils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/winezer0/xutils/cacher"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// TestResumeIterator 测试提交检查点后从下一条记录恢复，行号与表头保持一致
+func TestResumeIterator(t *testing.T) {
+	content := "\ufeffexported\nid,name\n1,\"a\nb\"\n2,bob\n\n3,carl\n4,dan\n"
+	filePath := makeTempCSV(t, "resume.csv", content)
+	store := NewFileCheckpointStore(DefaultCheckpointPath(filePath))
+	opts := DefaultReadOptions()
+	opts.SkipRows = 1
+
+	iter, err := ResumeWithOptions(filePath, opts, store)
+	if err != nil {
+		t.Fatalf("create iterator failed: %v", err)
+	}
+	iter.Next()
+	iter.Next()
+	if err := iter.Commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	iter.Next() // 未提交的记录在恢复后重新读取
+	iter.Close()
+
+	resumed, err := Resume(filePath)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	defer resumed.Close()
+	if !reflect.DeepEqual(resumed.Header(), []string{"id", "name"}) {
+		t.Errorf("unexpected header: %v", resumed.Header())
+	}
+	var names []interface{}
+	var lines []int
+	for row := resumed.Next(); row != nil; row = resumed.Next() {
+		names = append(names, row["name"])
+		lines = append(lines, resumed.Line())
+	}
+	if !reflect.DeepEqual(names, []interface{}{"carl", "dan"}) || !reflect.DeepEqual(lines, []int{7, 8}) {
+		t.Errorf("unexpected resumed rows: %v, lines %v", names, lines)
+	}
+	if cp := resumed.Checkpoint(); cp.Rows != 4 {
+		t.Errorf("unexpected rows in checkpoint: %+v", cp)
+	}
+}
+
+// TestReadBigCSVToDictsWithCheckpoint 测试回调失败后从最后提交的位置继续处理，包括非 UTF-8 编码与 cacher 存储
+func TestReadBigCSVToDictsWithCheckpoint(t *testing.T) {
+	encoded, err := simplifiedchinese.GBK.NewEncoder().String("名称,数量\n苹果,1\n香蕉,2\n橙子,3\n")
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "gbk.csv")
+	if err := os.WriteFile(filePath, []byte(encoded), 0644); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+
+	cache := cacher.NewCacheManager(filepath.Join(dir, "cache.json"))
+	defer cache.Close()
+	store := NewCacheCheckpointStore(cache, filePath)
+	opts := DefaultReadOptions()
+	opts.Encoding = "gbk"
+
+	var names []interface{}
+	failOn := "橙子"
+	callback := func(m map[string]interface{}) error {
+		if m["名称"] == failOn {
+			return errors.New("crash")
+		}
+		names = append(names, m["名称"])
+		return nil
+	}
+	if _, err := ReadBigCSVToDictsWithCheckpoint(filePath, opts, store, 1, callback); err == nil {
+		t.Fatal("expected callback error")
+	}
+
+	failOn = ""
+	summary, err := ReadBigCSVToDictsWithCheckpoint(filePath, opts, store, 1, callback)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []interface{}{"苹果", "香蕉", "橙子"}) || summary.Read != 1 {
+		t.Errorf("unexpected names: %v, summary %+v", names, summary)
+	}
+	if cp, ok, err := store.Load(); err != nil || !ok || cp.Rows != 3 || cp.Line != 4 {
+		t.Errorf("unexpected final checkpoint: %+v, %v, %v", cp, ok, err)
+	}
+}
@@ -1,8 +1,8 @@
 package csvutils
 
 import (
-	"encoding/csv"
-	"os"
+	"errors"
+	"io"
 )
 
 // CountCSVLines 统计指定 CSV 文件的总行数（记录数）。
@@ -10,27 +10,32 @@ import (
 //
 // 参数说明：
 //   - filePath: CSV 文件的绝对或相对路径。
-//   - delimiter: CSV 文件的分隔符（如 ',' 或 '\t'）。
+//   - delimiter: CSV 文件的分隔符（如 ',' 或 '\t'），0 表示默认逗号。
 //
 // 返回值：
 //   - int: 文件的总行数。
 //   - error: 执行过程中遇到的错误（如文件不存在、权限不足或读取失败）。
 func CountCSVLines(filePath string, delimiter rune) (int, error) {
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
+	return CountCSVLinesWithOptions(filePath, plainReadOptions(delimiter, HeaderNone))
+}
+
+// CountCSVLinesWithOptions 按读取选项统计 CSV 文件的数据记录数，HeaderMode 为 HeaderNone 时表头行也计入。
+// 引号内的换行不会被重复计数，空文件返回 0。
+func CountCSVLinesWithOptions(filePath string, opts ReadOptions) (int, error) {
+	src, err := openCSVSource(filePath, opts)
 	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
 		return 0, err
 	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	reader.Comma = delimiter
-	reader.FieldsPerRecord = -1 // 允许每行字段数不一致
+	defer src.Close()
 
 	count := 0
 	for {
-		_, err := reader.Read()
+		_, err := src.Read()
 		if err != nil {
-			if err.Error() == "EOF" {
+			if errors.Is(err, io.EOF) {
 				break
 			}
 			return 0, err
@@ -0,0 +1,78 @@
+package csvutils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestWriteReadEncoding 测试按指定编码写入并自动检测编码读取
+func TestWriteReadEncoding(t *testing.T) {
+	header := []string{"姓名", "城市"}
+	rows := [][]string{{"张三", "北京"}, {"李四", "上海"}}
+
+	cases := []struct {
+		encoding string
+		bom      bool
+		prefix   []byte
+	}{
+		{encoding: "utf-8", bom: true, prefix: []byte{0xEF, 0xBB, 0xBF}},
+		{encoding: "utf-16le", bom: true, prefix: []byte{0xFF, 0xFE}},
+		{encoding: "utf-16be", bom: true, prefix: []byte{0xFE, 0xFF}},
+		{encoding: "gbk"},
+	}
+	for _, c := range cases {
+		t.Run(c.encoding, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "enc.csv")
+			opts := WriteOptions{Encoding: c.encoding, BOM: c.bom}
+			if err := WriteRowsToCSVWithOptions(filePath, header, rows[:1], true, opts); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			// 追加时读取已有表头，不重复写入表头和 BOM
+			if err := WriteRowsToCSVWithOptions(filePath, header, rows[1:], false, opts); err != nil {
+				t.Fatalf("append failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("read file failed: %v", err)
+			}
+			if !bytes.HasPrefix(data, c.prefix) || len(c.prefix) > 0 && bytes.Count(data, c.prefix) != 1 {
+				t.Errorf("unexpected bom: % x", data[:4])
+			}
+
+			readOpts := DefaultReadOptions()
+			if !c.bom {
+				readOpts.Encoding = c.encoding
+			}
+			gotHeader, gotRows, err := ReadCSV2RowsWithOptions(filePath, readOpts)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if !reflect.DeepEqual(gotHeader, header) || !reflect.DeepEqual(gotRows, rows) {
+				t.Errorf("unexpected content: %v %v", gotHeader, gotRows)
+			}
+		})
+	}
+}
+
+// TestWriteUnknownEncoding 测试无法识别的编码直接报错，不截断已有文件
+func TestWriteUnknownEncoding(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "enc.csv")
+	if err := os.WriteFile(filePath, []byte("name\nalice\n"), 0644); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	opts := WriteOptions{Encoding: "shift_jis", BOM: true}
+	if err := WriteRowsToCSVWithOptions(filePath, []string{"name"}, [][]string{{"bob"}}, true, opts); err == nil {
+		t.Fatal("expected error for unknown encoding")
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("read file failed: %v", err)
+	}
+	if string(data) != "name\nalice\n" {
+		t.Errorf("file modified: %q", data)
+	}
+}
@@ -1,10 +1,8 @@
 package csvutils
 
 import (
-	"encoding/csv"
 	"fmt"
 	"github.com/winezer0/xutils/utils"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -21,28 +19,24 @@ import (
 //   - []string: 清洗后的表头字符串切片。
 //   - error: 执行过程中遇到的错误（如文件打开失败、读取失败）。
 func GetCSVHeaders(filePath string, delimiter rune) ([]string, error) {
-	// 处理空分隔符：默认设为逗号
-	if delimiter == 0 { // 核心判断逻辑
-		delimiter, _ = DetectCSVDelimiter(filePath)
-	}
+	return GetCSVHeadersWithOptions(filePath, plainHeaderReadOptions(delimiter))
+}
 
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
+// plainHeaderReadOptions GetCSVHeaders 使用的选项，分隔符为 0 时自动检测，表头经过 RepairHeaders 清洗
+func plainHeaderReadOptions(delimiter rune) ReadOptions {
+	opts := plainReadOptions(delimiter, HeaderFirstRow)
+	opts.Delimiter = delimiter
+	return opts
+}
 
-	reader := csv.NewReader(file)
-	reader.Comma = delimiter
-	reader.FieldsPerRecord = -1 // 允许每行字段数不一致
-	headers, err := reader.Read()
+// GetCSVHeadersWithOptions 按读取选项获取 CSV 文件的列名，HeaderMode 为 HeaderNone 或 HeaderIgnore 时返回 col0、col1... 形式的默认列名。
+func GetCSVHeadersWithOptions(filePath string, opts ReadOptions) ([]string, error) {
+	src, err := openCSVSource(filePath, opts)
 	if err != nil {
 		return nil, err
 	}
-
-	headers = RepairHeaders(headers)
-
-	return headers, nil
+	defer src.Close()
+	return src.Header(), nil
 }
 
 // GetCSVSHeadersMap 从多个 CSV 文件中收集每个文件的头部字段
@@ -199,6 +193,11 @@ func GetCSVHeaderFromDicts(dicts []map[string]interface{}, header []string, deep
 //
 //	bool: true 表示需要写入表头，false 表示不需要（直接追加数据或跳过）。
 func ShouldWriteHeader(file string, header []string, overwrite bool, delimiter rune) (should bool) {
+	return shouldWriteHeader(file, header, overwrite, plainHeaderReadOptions(delimiter))
+}
+
+// shouldWriteHeader 按读取选项读取已有表头并判断是否需要写入表头
+func shouldWriteHeader(file string, header []string, overwrite bool, opts ReadOptions) (should bool) {
 	// 没有头部自然不需要写入头部
 	if len(header) == 0 {
 		return false
@@ -215,22 +214,22 @@ func ShouldWriteHeader(file string, header []string, overwrite bool, delimiter r
 	}
 
 	// 检查其他模式
-	oldHeaders, err := GetCSVHeaders(file, delimiter)
+	oldHeaders, err := GetCSVHeadersWithOptions(file, opts)
 	// 读取csv头失败，写入，避免用户找不到有效头部开始行
 	if err != nil {
-		fmt.Printf("file %s: read old header failed, err=%v, will write new header\n", file, err)
+		pkgLogger.Get().Warnf("file %s: read old header failed, err=%v, will write new header", file, err)
 		return true
 	}
 
 	// csv头部长度不对
 	if len(oldHeaders) != len(header) {
-		fmt.Printf("file %s: header mismatch (old=%v, new=%v), will write new header\n", file, len(oldHeaders), len(header))
+		pkgLogger.Get().Warnf("file %s: header mismatch (old=%v, new=%v), will write new header", file, len(oldHeaders), len(header))
 		return true
 	}
 
 	// csv头部顺序不对，写入，避免用户找不到有效头部开始行 清洗新表头，确保与旧表头使用相同的修复规则
 	if !utils.SliceEqualStrict(RepairHeaders(oldHeaders), RepairHeaders(header)) {
-		fmt.Printf("file %s: header mismatch (old=%v, new=%v), will write new header\n", file, oldHeaders, header)
+		pkgLogger.Get().Warnf("file %s: header mismatch (old=%v, new=%v), will write new header", file, oldHeaders, header)
 		return true
 	}
 
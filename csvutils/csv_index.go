@@ -0,0 +1,250 @@
+package csvutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// CSVIndex CSV 行索引，每隔 Interval 条数据记录保存一次记录的起始位置，用于随机访问与分片并行读取。
+// 位置来自 CSV 解析器，引号内的换行不会造成错位；记录序号从 0 开始，格式错误的记录同样占用一个序号
+type CSVIndex struct {
+	Path      string       `json:"path"`
+	Interval  int          `json:"interval"`   // 索引间隔
+	Rows      int          `json:"rows"`       // 数据记录总数
+	Header    []string     `json:"header"`     // 列名
+	Delimiter rune         `json:"delimiter"`  // 分隔符
+	Encoding  string       `json:"encoding"`   // 文件编码
+	FileSize  int64        `json:"file_size"`  // 建立索引时的文件大小
+	EndOffset int64        `json:"end_offset"` // 最后一条记录结束处的偏移
+	Entries   []IndexEntry `json:"entries"`    // Entries[i] 为第 i*Interval 条记录的起始位置
+
+	opts ReadOptions // 读取记录时使用的选项
+}
+
+// IndexEntry 记录的起始位置，偏移的含义与 Checkpoint.Offset 相同
+type IndexEntry struct {
+	Offset int64 `json:"offset"` // 记录起始字节偏移
+	Line   int   `json:"line"`   // 记录之前的物理行数
+}
+
+// BuildCSVIndex 扫描整个文件建立行索引，interval <= 0 时为 1000。
+// 间隔越小随机访问越快，索引越大；非 UTF-8 编码的文件定位时需要解码偏移之前的数据
+func BuildCSVIndex(filePath string, interval int, opts ReadOptions) (*CSVIndex, error) {
+	if interval <= 0 {
+		interval = 1000
+	}
+	src, err := openCSVSource(filePath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("read header failed: %w", err)
+	}
+	defer src.Close()
+
+	start := src.checkpoint(0)
+	idx := &CSVIndex{
+		Path:      filePath,
+		Interval:  interval,
+		Header:    start.Header,
+		Delimiter: start.Delimiter,
+		Encoding:  start.Encoding,
+		FileSize:  start.FileSize,
+		opts:      src.opts,
+	}
+	cur := IndexEntry{Offset: start.Offset, Line: start.Line}
+	for row := 0; ; row++ {
+		if row%interval == 0 {
+			idx.Entries = append(idx.Entries, cur)
+		}
+		if _, err := src.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				if row%interval == 0 {
+					idx.Entries = idx.Entries[:len(idx.Entries)-1]
+				}
+				idx.Rows = row
+				idx.EndOffset = cur.Offset
+				return idx, nil
+			}
+			if !isRowError(err) {
+				return nil, fmt.Errorf("read row %d failed: %w", src.Line(), err)
+			}
+		}
+		cur = IndexEntry{Offset: src.pos.offset, Line: src.pos.endLine}
+	}
+}
+
+// LoadCSVIndex 加载 Save 保存的索引，opts 需与建立索引时的注释、引号等解析规则一致
+func LoadCSVIndex(indexPath string, opts ReadOptions) (*CSVIndex, error) {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("read index failed: %w", err)
+	}
+	idx := &CSVIndex{}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("parse index %s failed: %w", indexPath, err)
+	}
+	if idx.Interval <= 0 || len(idx.Header) == 0 {
+		return nil, fmt.Errorf("invalid index %s", indexPath)
+	}
+	if idx.opts, err = normalizeReadOptions(opts); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Save 将索引原子地保存为 JSON 文件
+func (idx *CSVIndex) Save(indexPath string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshal index failed: %w", err)
+	}
+	if err := utils.WriteBytesAtomic(indexPath, data); err != nil {
+		return fmt.Errorf("write index failed: %w", err)
+	}
+	return nil
+}
+
+// ReadRow 读取第 row 条数据记录，该记录格式错误或字段超长时返回 *RowError
+func (idx *CSVIndex) ReadRow(row int) ([]string, error) {
+	if row < 0 || row >= idx.Rows {
+		return nil, fmt.Errorf("row %d out of range [0, %d)", row, idx.Rows)
+	}
+	opts := idx.opts
+	opts.Strict = true
+	var record []string
+	err := idx.scan(row, row+1, opts, func(_ int, r []string) error {
+		record = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ReadRange 读取 [startRow, endRow) 范围内的数据记录，endRow 超出记录总数时截断到记录总数。
+// 格式错误或字段超长的记录与建立索引时一样占用序号，按 Strict 与 OnRowError 处理，宽松模式下不出现在结果中
+func (idx *CSVIndex) ReadRange(startRow, endRow int) ([][]string, error) {
+	var rows [][]string
+	err := idx.scan(startRow, endRow, idx.opts, func(_ int, record []string) error {
+		rows = append(rows, record)
+		return nil
+	})
+	return rows, err
+}
+
+// scan 从最近的索引位置开始读取 [startRow, endRow) 范围内的记录并交给 fn 处理，失败的记录按 opts 处理
+func (idx *CSVIndex) scan(startRow, endRow int, opts ReadOptions, fn func(row int, record []string) error) error {
+	if startRow < 0 || startRow > endRow {
+		return fmt.Errorf("invalid row range [%d, %d)", startRow, endRow)
+	}
+	if endRow > idx.Rows {
+		endRow = idx.Rows
+	}
+	if startRow >= endRow {
+		return nil
+	}
+
+	entryIdx := startRow / idx.Interval
+	entry := idx.Entries[entryIdx]
+	src, err := openCSVSourceAt(idx.Path, opts, Checkpoint{
+		Offset:    entry.Offset,
+		Line:      entry.Line,
+		Header:    idx.Header,
+		Delimiter: idx.Delimiter,
+		Encoding:  idx.Encoding,
+		FileSize:  idx.FileSize,
+	})
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var summary ReadSummary
+	for row := entryIdx * idx.Interval; row < endRow; row++ {
+		record, err := src.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("unexpected end of file at row %d, index may be stale", row)
+			}
+			if !isRowError(err) {
+				return fmt.Errorf("read row %d (line %d) failed: %w", row, src.Line(), err)
+			}
+			// 与 BuildCSVIndex 一致，失败的记录同样占用一个序号
+			if row >= startRow {
+				if err := src.rowFailed(&summary, record, err); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if row < startRow {
+			continue
+		}
+		if err := fn(row, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSVShard 按记录边界划分的文件分片，不同分片使用各自的文件句柄，可并发读取
+type CSVShard struct {
+	Index    int   // 分片序号
+	StartRow int   // 起始记录序号（含）
+	EndRow   int   // 结束记录序号（不含）
+	Start    int64 // 起始字节偏移
+	End      int64 // 结束字节偏移，即下一个分片的起始偏移
+
+	idx *CSVIndex
+}
+
+// Shards 按字节大小将文件划分为最多 n 个分片，分片边界取最接近的索引位置，
+// 因此分片粒度为 Interval 条记录，记录数少于 n*Interval 时分片数可能少于 n
+func (idx *CSVIndex) Shards(n int) []CSVShard {
+	if n <= 0 || idx.Rows == 0 {
+		return nil
+	}
+	first := idx.Entries[0].Offset
+	size := idx.EndOffset - first
+
+	// 每个分片的起始索引位置，保持严格递增
+	starts := []int{0}
+	for k := 1; k < n; k++ {
+		target := first + size*int64(k)/int64(n)
+		i := sort.Search(len(idx.Entries), func(i int) bool { return idx.Entries[i].Offset >= target })
+		if i < len(idx.Entries) && i > starts[len(starts)-1] {
+			starts = append(starts, i)
+		}
+	}
+
+	shards := make([]CSVShard, len(starts))
+	for k, entryIdx := range starts {
+		shard := CSVShard{
+			Index:    k,
+			StartRow: entryIdx * idx.Interval,
+			EndRow:   idx.Rows,
+			Start:    idx.Entries[entryIdx].Offset,
+			End:      idx.EndOffset,
+			idx:      idx,
+		}
+		if k+1 < len(starts) {
+			shard.EndRow = starts[k+1] * idx.Interval
+			shard.End = idx.Entries[starts[k+1]].Offset
+		}
+		shards[k] = shard
+	}
+	return shards
+}
+
+// Read 读取分片内的全部记录并交给 fn 处理，row 为记录在文件中的序号，失败的记录处理方式同 ReadRange，fn 返回错误时终止读取
+func (s CSVShard) Read(fn func(row int, record []string) error) error {
+	if fn == nil {
+		return errors.New("callback function cannot be nil")
+	}
+	return s.idx.scan(s.StartRow, s.EndRow, s.idx.opts, fn)
+}
@@ -0,0 +1,115 @@
+package csvutils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCSVIndex 测试索引随机访问、保存加载以及分片并发读取，字段中包含引号内换行
+func TestCSVIndex(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,text\n")
+	for i := 0; i < 100; i++ {
+		if i%7 == 0 {
+			fmt.Fprintf(&b, "%d,\"line\n%d\"\n", i, i)
+		} else {
+			fmt.Fprintf(&b, "%d,text%d\n", i, i)
+		}
+	}
+	filePath := makeTempCSV(t, "indexed.csv", b.String())
+
+	idx, err := BuildCSVIndex(filePath, 3, DefaultReadOptions())
+	if err != nil {
+		t.Fatalf("build index failed: %v", err)
+	}
+	if idx.Rows != 100 || len(idx.Entries) != 34 {
+		t.Fatalf("unexpected index: rows %d, entries %d", idx.Rows, len(idx.Entries))
+	}
+
+	rows, err := idx.ReadRange(13, 16)
+	if err != nil {
+		t.Fatalf("read range failed: %v", err)
+	}
+	expected := [][]string{{"13", "text13"}, {"14", "line\n14"}, {"15", "text15"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("unexpected range: %q", rows)
+	}
+
+	indexPath := filepath.Join(t.TempDir(), "indexed.idx")
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("save index failed: %v", err)
+	}
+	loaded, err := LoadCSVIndex(indexPath, DefaultReadOptions())
+	if err != nil {
+		t.Fatalf("load index failed: %v", err)
+	}
+	if row, err := loaded.ReadRow(99); err != nil || !reflect.DeepEqual(row, []string{"99", "text99"}) {
+		t.Errorf("unexpected last row: %q, %v", row, err)
+	}
+	if _, err := loaded.ReadRow(100); err == nil {
+		t.Error("expected out of range error")
+	}
+
+	shards := idx.Shards(4)
+	if len(shards) != 4 || shards[0].StartRow != 0 || shards[3].EndRow != 100 {
+		t.Fatalf("unexpected shards: %+v", shards)
+	}
+	seen := make([]string, 100)
+	var wg sync.WaitGroup
+	errs := make([]error, len(shards))
+	for i, shard := range shards {
+		if i > 0 && (shard.StartRow != shards[i-1].EndRow || shard.Start != shards[i-1].End) {
+			t.Errorf("shard %d not contiguous: %+v", i, shard)
+		}
+		wg.Add(1)
+		go func(i int, shard CSVShard) {
+			defer wg.Done()
+			errs[i] = shard.Read(func(row int, record []string) error {
+				seen[row] = record[0]
+				return nil
+			})
+		}(i, shard)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("shard %d read failed: %v", i, err)
+		}
+	}
+	for i, id := range seen {
+		if id != fmt.Sprint(i) {
+			t.Fatalf("row %d read as %q", i, id)
+		}
+	}
+}
+
+// TestCSVIndexRowErrors 测试字段超长的记录在建立索引与读取时占用相同的序号
+func TestCSVIndexRowErrors(t *testing.T) {
+	filePath := makeTempCSV(t, "index_errors.csv", "id,text\n0,a\n1,toolong\n2,c\n")
+	opts := DefaultReadOptions()
+	opts.MaxFieldSize = 5
+	idx, err := BuildCSVIndex(filePath, 2, opts)
+	if err != nil || idx.Rows != 3 {
+		t.Fatalf("build index failed: %v, %+v", err, idx)
+	}
+
+	rows, err := idx.ReadRange(0, 3)
+	if err != nil {
+		t.Fatalf("read range failed: %v", err)
+	}
+	if !reflect.DeepEqual(rows, [][]string{{"0", "a"}, {"2", "c"}}) {
+		t.Errorf("unexpected range: %q", rows)
+	}
+	if row, err := idx.ReadRow(2); err != nil || !reflect.DeepEqual(row, []string{"2", "c"}) {
+		t.Errorf("unexpected row 2: %q, %v", row, err)
+	}
+	var rowErr *RowError
+	if _, err := idx.ReadRow(1); !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("expected row error at line 3, got %v", err)
+	}
+}
@@ -0,0 +1,342 @@
+package csvutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// MergeOptions 合并多个 CSV 文件的选项
+type MergeOptions struct {
+	Read         ReadOptions  // 输入文件的读取选项
+	Write        WriteOptions // 输出文件的写入选项
+	Header       []string     // 输出列，为空时按输入文件顺序合并各文件的列名，不在其中的列被丢弃
+	SourceColumn string       // 非空时在末尾添加该列，记录每行来源的文件路径
+}
+
+// DefaultMergeOptions 返回默认合并选项
+func DefaultMergeOptions() MergeOptions {
+	return MergeOptions{Read: DefaultReadOptions(), Write: DefaultWriteOptions()}
+}
+
+// MergeCSVFiles 将多个 CSV 文件按列名对齐后合并写入 outPath（覆盖写入），文件缺少的列留空，返回写入的数据行数。
+// 输入文件逐行流式读取，适合合并大量扫描结果
+func MergeCSVFiles(inputs []string, outPath string, opts MergeOptions) (count int, err error) {
+	if len(inputs) == 0 {
+		return 0, errors.New("no input files")
+	}
+
+	// 1. 确定输出列：未指定时按文件顺序收集所有列名
+	header := opts.Header
+	if len(header) == 0 {
+		seen := make(map[string]bool)
+		for _, input := range inputs {
+			fileHeader, err := GetCSVHeadersWithOptions(input, opts.Read)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					continue // 空文件
+				}
+				return 0, fmt.Errorf("read headers failed for %s: %w", input, err)
+			}
+			for _, col := range fileHeader {
+				if !seen[col] {
+					seen[col] = true
+					header = append(header, col)
+				}
+			}
+		}
+	}
+	outHeader := header
+	if opts.SourceColumn != "" {
+		outHeader = append(append([]string(nil), header...), opts.SourceColumn)
+	}
+
+	writer, err := openCSVWriter(outPath, true, opts.Write)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cErr := writer.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf("csv writer flush error for %s: %w", outPath, cErr)
+		}
+	}()
+	if err := writer.Write(outHeader); err != nil {
+		return 0, fmt.Errorf("write header to %s failed: %w", outPath, err)
+	}
+
+	// 2. 逐个文件按列名映射后写入
+	for _, input := range inputs {
+		n, err := mergeCSVFile(writer, input, header, opts)
+		count += n
+		if err != nil {
+			return count, fmt.Errorf("merge %s failed: %w", input, err)
+		}
+	}
+	return count, nil
+}
+
+// mergeCSVFile 将单个文件的记录按输出列对齐后写入
+func mergeCSVFile(w RecordWriter, input string, header []string, opts MergeOptions) (int, error) {
+	src, err := openCSVSource(input, opts.Read)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil // 空文件
+		}
+		return 0, err
+	}
+	defer src.Close()
+
+	// mapping[i] 为输出第 i 列在该文件中的列索引，-1 表示该文件没有此列
+	columns := make(map[string]int, len(src.Header()))
+	for i, col := range src.Header() {
+		columns[col] = i
+	}
+	mapping := make([]int, len(header))
+	for i, col := range header {
+		mapping[i] = -1
+		if idx, ok := columns[col]; ok {
+			mapping[i] = idx
+		}
+	}
+
+	count := 0
+	var summary ReadSummary
+	for {
+		record, err := src.nextRecord(&summary)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			return count, err
+		}
+		row := make([]string, len(header), len(header)+1)
+		for i, idx := range mapping {
+			if idx >= 0 && idx < len(record) {
+				row[i] = record[idx]
+			}
+		}
+		if opts.SourceColumn != "" {
+			row = append(row, input)
+		}
+		if err := w.Write(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// JoinType 连接类型
+type JoinType int
+
+const (
+	InnerJoin JoinType = iota // 内连接，只输出两侧都存在的键
+	LeftJoin                  // 左连接，输出左表全部记录，右表缺失的列留空
+	OuterJoin                 // 全外连接，额外输出右表中未匹配的记录，左表缺失的列留空
+)
+
+// JoinOptions 连接 CSV 文件的选项
+type JoinOptions struct {
+	Read  ReadOptions  // 两侧文件的读取选项
+	Write WriteOptions // 输出文件的写入选项
+}
+
+// JoinResult 连接结果
+type JoinResult struct {
+	Rows  int         // 写入的数据行数
+	Left  ReadSummary // 左表的读取统计
+	Right ReadSummary // 右表的读取统计
+}
+
+// DefaultJoinOptions 返回默认连接选项
+func DefaultJoinOptions() JoinOptions {
+	return JoinOptions{Read: DefaultReadOptions(), Write: DefaultWriteOptions()}
+}
+
+// JoinCSVFiles 按 keys 列连接两个 CSV 文件并写入 outPath（覆盖写入），返回写入的数据行数。
+// 采用哈希连接：右表全部加载到内存，左表流式读取，右表应为较小的一侧；右表同一键的多条记录分别与左表记录组合。
+// 输出列为左表全部列加右表的非键列，与左表同名的右表列以 "右表文件名.列名" 命名。
+// 与 MergeCSVFiles 一样接受空文件，空文件视为只有键列、没有记录的表
+func JoinCSVFiles(left, right string, keys []string, joinType JoinType, outPath string, opts JoinOptions) (count int, err error) {
+	result, err := JoinCSVFilesWithSummary(left, right, keys, joinType, outPath, opts)
+	return result.Rows, err
+}
+
+// JoinCSVFilesWithSummary 与 JoinCSVFiles 相同，同时返回两侧文件的读取统计。
+// 两侧失败的记录均按 opts.Read 的 Strict 与 OnRowError 处理
+func JoinCSVFilesWithSummary(left, right string, keys []string, joinType JoinType, outPath string, opts JoinOptions) (result JoinResult, err error) {
+	if len(keys) == 0 {
+		return result, errors.New("join keys cannot be empty")
+	}
+	if joinType < InnerJoin || joinType > OuterJoin {
+		return result, fmt.Errorf("invalid join type: %d", joinType)
+	}
+
+	// 1. 加载右表并按键分组
+	rightHeader, rightRows, err := loadJoinTable(right, opts.Read, &result.Right)
+	if err != nil {
+		return result, fmt.Errorf("read right file %s failed: %w", right, err)
+	}
+	if rightHeader == nil {
+		rightHeader = keys // 空文件
+	}
+	rightKeyIdx, err := GetKeyIndices(rightHeader, keys)
+	if err != nil {
+		return result, fmt.Errorf("right file %s: %w", right, err)
+	}
+	groups := make(map[string][]int, len(rightRows))
+	for i, row := range rightRows {
+		key := joinKey(row, rightKeyIdx)
+		groups[key] = append(groups[key], i)
+	}
+
+	// 2. 打开左表并确定输出列
+	var leftHeader []string
+	src, err := openCSVSource(left, opts.Read)
+	switch {
+	case err == nil:
+		defer src.Close()
+		leftHeader = src.Header()
+	case errors.Is(err, io.EOF):
+		leftHeader = keys // 空文件，没有需要读取的记录
+	default:
+		return result, fmt.Errorf("read left file %s failed: %w", left, err)
+	}
+	leftKeyIdx, err := GetKeyIndices(leftHeader, keys)
+	if err != nil {
+		return result, fmt.Errorf("left file %s: %w", left, err)
+	}
+
+	isKey := make(map[int]bool, len(rightKeyIdx))
+	for _, idx := range rightKeyIdx {
+		isKey[idx] = true
+	}
+	leftCols := make(map[string]bool, len(leftHeader))
+	for _, col := range leftHeader {
+		leftCols[col] = true
+	}
+	baseName := filepath.Base(right)
+	rightName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	outHeader := append([]string(nil), leftHeader...)
+	var rightExtra []int // 输出的右表非键列
+	for i, col := range rightHeader {
+		if isKey[i] {
+			continue
+		}
+		if leftCols[col] {
+			col = fmt.Sprintf("%s.%s", rightName, col)
+		}
+		outHeader = append(outHeader, col)
+		rightExtra = append(rightExtra, i)
+	}
+
+	writer, err := openCSVWriter(outPath, true, opts.Write)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if cErr := writer.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf("csv writer flush error for %s: %w", outPath, cErr)
+		}
+	}()
+	if err := writer.Write(outHeader); err != nil {
+		return result, fmt.Errorf("write header to %s failed: %w", outPath, err)
+	}
+
+	// 3. 流式读取左表并与右表匹配
+	matched := make(map[string]bool)
+	leftIdx := seqIndices(len(leftHeader)) // 左表记录按表头补齐或截断
+	for src != nil {
+		record, err := src.nextRecord(&result.Left)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return result, err
+		}
+		leftRow := pickFields(record, leftIdx)
+		key := joinKey(record, leftKeyIdx)
+		group, ok := groups[key]
+		if !ok {
+			if joinType == InnerJoin {
+				continue
+			}
+			group = []int{-1} // 左表记录保留，右表列留空
+		} else {
+			matched[key] = true
+		}
+		for _, ri := range group {
+			row := make([]string, 0, len(outHeader))
+			row = append(row, leftRow...)
+			if ri >= 0 {
+				row = append(row, pickFields(rightRows[ri], rightExtra)...)
+			} else {
+				row = append(row, make([]string, len(rightExtra))...)
+			}
+			if err := writer.Write(row); err != nil {
+				return result, fmt.Errorf("write row to %s failed: %w", outPath, err)
+			}
+			result.Rows++
+		}
+	}
+
+	// 4. 全外连接：输出右表未匹配的记录，键值填入左表的键列
+	if joinType == OuterJoin {
+		for _, rightRow := range rightRows {
+			if matched[joinKey(rightRow, rightKeyIdx)] {
+				continue
+			}
+			row := make([]string, len(leftHeader), len(outHeader))
+			for k, idx := range leftKeyIdx {
+				if rightKeyIdx[k] < len(rightRow) {
+					row[idx] = rightRow[rightKeyIdx[k]]
+				}
+			}
+			row = append(row, pickFields(rightRow, rightExtra)...)
+			if err := writer.Write(row); err != nil {
+				return result, fmt.Errorf("write row to %s failed: %w", outPath, err)
+			}
+			result.Rows++
+		}
+	}
+	return result, nil
+}
+
+// loadJoinTable 读取连接右表的列名与全部记录，空文件返回 nil 列名
+func loadJoinTable(filePath string, opts ReadOptions, summary *ReadSummary) ([]string, [][]string, error) {
+	src, err := openCSVSource(filePath, opts)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	defer src.Close()
+
+	var rows [][]string
+	for {
+		record, err := src.nextRecord(summary)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return src.Header(), rows, nil
+			}
+			return nil, nil, err
+		}
+		rows = append(rows, record)
+	}
+}
+
+// joinKey 将键列的值拼接为连接键
+func joinKey(record []string, indices []int) string {
+	return strings.Join(pickFields(record, indices), "\x00")
+}
+
+// seqIndices 返回 0..n-1 的列索引
+func seqIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
@@ -0,0 +1,125 @@
+package csvutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMergeCSVFiles 测试按列名对齐合并与来源列
+func TestMergeCSVFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.csv")
+	b := filepath.Join(dir, "b.csv")
+	empty := filepath.Join(dir, "empty.csv")
+	os.WriteFile(a, []byte("ip,port\n1.1.1.1,80\n"), 0644)
+	os.WriteFile(b, []byte("port,title,ip\n443,home,2.2.2.2\n"), 0644)
+	os.WriteFile(empty, nil, 0644)
+
+	outPath := filepath.Join(dir, "out.csv")
+	opts := DefaultMergeOptions()
+	opts.SourceColumn = "source"
+	count, err := MergeCSVFiles([]string{a, empty, b}, outPath, opts)
+	if err != nil || count != 2 {
+		t.Fatalf("merge failed: %v, %d rows", err, count)
+	}
+	data, _ := os.ReadFile(outPath)
+	expected := "ip,port,title,source\n1.1.1.1,80,," + a + "\n2.2.2.2,443,home," + b + "\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+// TestJoinCSVFiles 测试内连接、左连接与全外连接
+func TestJoinCSVFiles(t *testing.T) {
+	dir := t.TempDir()
+	left := filepath.Join(dir, "hosts.csv")
+	right := filepath.Join(dir, "titles.csv")
+	os.WriteFile(left, []byte("ip,port,status\n1.1.1.1,80,open\n2.2.2.2,22,open\n"), 0644)
+	os.WriteFile(right, []byte("ip,port,status,title\n1.1.1.1,80,200,home\n1.1.1.1,80,301,redirect\n3.3.3.3,443,200,api\n"), 0644)
+
+	cases := []struct {
+		joinType JoinType
+		expected string
+	}{
+		{InnerJoin, "ip,port,status,titles.status,title\n1.1.1.1,80,open,200,home\n1.1.1.1,80,open,301,redirect\n"},
+		{LeftJoin, "ip,port,status,titles.status,title\n1.1.1.1,80,open,200,home\n1.1.1.1,80,open,301,redirect\n2.2.2.2,22,open,,\n"},
+		{OuterJoin, "ip,port,status,titles.status,title\n1.1.1.1,80,open,200,home\n1.1.1.1,80,open,301,redirect\n2.2.2.2,22,open,,\n3.3.3.3,443,,200,api\n"},
+	}
+	outPath := filepath.Join(dir, "out.csv")
+	for _, c := range cases {
+		if _, err := JoinCSVFiles(left, right, []string{"ip", "port"}, c.joinType, outPath, DefaultJoinOptions()); err != nil {
+			t.Fatalf("join type %d failed: %v", c.joinType, err)
+		}
+		data, _ := os.ReadFile(outPath)
+		if string(data) != c.expected {
+			t.Errorf("join type %d unexpected output:\n%s", c.joinType, data)
+		}
+	}
+
+	if _, err := JoinCSVFiles(left, right, []string{"missing"}, InnerJoin, outPath, DefaultJoinOptions()); err == nil {
+		t.Error("expected missing key error")
+	}
+}
+
+// TestJoinCSVFilesRowErrors 测试右表失败的记录与左表一样按 Strict 处理，并分别统计两侧
+func TestJoinCSVFilesRowErrors(t *testing.T) {
+	dir := t.TempDir()
+	left := filepath.Join(dir, "left.csv")
+	right := filepath.Join(dir, "right.csv")
+	os.WriteFile(left, []byte("ip,note\n1.1.1.1,ok\n2.2.2.2,a very long note\n"), 0644)
+	os.WriteFile(right, []byte("ip,title\n1.1.1.1,home\n2.2.2.2,a very long title\n"), 0644)
+
+	opts := DefaultJoinOptions()
+	opts.Read.MaxFieldSize = 10
+	outPath := filepath.Join(dir, "out.csv")
+	result, err := JoinCSVFilesWithSummary(left, right, []string{"ip"}, LeftJoin, outPath, opts)
+	if err != nil {
+		t.Fatalf("join failed: %v", err)
+	}
+	if result.Rows != 1 || result.Left.Skipped != 1 || result.Right.Skipped != 1 || result.Right.Read != 2 {
+		t.Errorf("unexpected join result: %+v", result)
+	}
+	data, _ := os.ReadFile(outPath)
+	if string(data) != "ip,note,title\n1.1.1.1,ok,home\n" {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+
+	opts.Read.Strict = true
+	if _, err := JoinCSVFiles(left, right, []string{"ip"}, LeftJoin, outPath, opts); err == nil {
+		t.Error("expected strict mode error")
+	}
+}
+
+// TestJoinCSVFilesEmptyInput 测试空文件视为只有键列、没有记录的表
+func TestJoinCSVFilesEmptyInput(t *testing.T) {
+	dir := t.TempDir()
+	hosts := filepath.Join(dir, "hosts.csv")
+	titles := filepath.Join(dir, "titles.csv")
+	empty := filepath.Join(dir, "empty.csv")
+	os.WriteFile(hosts, []byte("ip,port,status\n1.1.1.1,80,open\n"), 0644)
+	os.WriteFile(titles, []byte("ip,port,title\n3.3.3.3,443,api\n"), 0644)
+	os.WriteFile(empty, nil, 0644)
+
+	cases := []struct {
+		name        string
+		left, right string
+		joinType    JoinType
+		expected    string
+	}{
+		{"empty right left join", hosts, empty, LeftJoin, "ip,port,status\n1.1.1.1,80,open\n"},
+		{"empty right inner join", hosts, empty, InnerJoin, "ip,port,status\n"},
+		{"empty left outer join", empty, titles, OuterJoin, "ip,port,title\n3.3.3.3,443,api\n"},
+		{"empty left inner join", empty, titles, InnerJoin, "ip,port,title\n"},
+	}
+	outPath := filepath.Join(dir, "out.csv")
+	for _, c := range cases {
+		if _, err := JoinCSVFiles(c.left, c.right, []string{"ip", "port"}, c.joinType, outPath, DefaultJoinOptions()); err != nil {
+			t.Fatalf("%s failed: %v", c.name, err)
+		}
+		data, _ := os.ReadFile(outPath)
+		if string(data) != c.expected {
+			t.Errorf("%s unexpected output:\n%s", c.name, data)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package csvutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/winezer0/xutils/liblog"
+)
+
+// PipelineOptions 并行处理管道选项
+type PipelineOptions struct {
+	Read        ReadOptions // 读取选项，失败的记录同样收集到 PipelineResult.Errors
+	Workers     int         // 工作协程数，0 表示 CPU 核心数
+	BufferSize  int         // 通道缓冲区大小，同时限制处理中及等待排序的记录数，0 表示 Workers*10
+	Ordered     bool        // 按输入顺序将结果交给 sink
+	StopOnError bool        // 任意记录处理失败时取消整个管道，默认收集错误后继续处理
+}
+
+// DefaultPipelineOptions 返回默认管道选项：默认读取选项、CPU 核心数个工作协程、不保证顺序
+func DefaultPipelineOptions() PipelineOptions {
+	return PipelineOptions{Read: DefaultReadOptions()}
+}
+
+// normalizePipelineOptions 补全管道选项
+func normalizePipelineOptions(opts PipelineOptions) PipelineOptions {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = opts.Workers * 10
+	}
+	return opts
+}
+
+// PipelineRow 交给处理函数的一条记录
+type PipelineRow struct {
+	Index int                    // 数据记录序号，从 0 开始，不含读取失败的记录
+	Line  int                    // 记录起始物理行号
+	Data  map[string]interface{} // 转换后的字典
+}
+
+// PipelineResult 管道运行结果
+type PipelineResult struct {
+	Summary   ReadSummary // 读取统计
+	Processed int         // 处理成功并交给 sink 的记录数
+	Errors    []*RowError // 读取或处理失败的记录，按行号排序
+}
+
+// Err 合并全部记录错误，没有错误时返回 nil
+func (r PipelineResult) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
+
+// pipelineItem 处理完成的记录
+type pipelineItem[R any] struct {
+	row   PipelineRow
+	value R
+	err   error
+}
+
+// RunCSVPipeline 以生产者+工作池的方式并行处理大文件：单个协程读取记录，opts.Workers 个协程调用 process，
+// 结果由调用方所在协程串行交给 sink（可为 nil），Ordered 为 true 时按输入顺序交付。
+// 记录级错误收集在 PipelineResult.Errors 中，返回的 error 仅表示文件读取失败、sink 失败、
+// StopOnError 触发或 ctx 被取消等导致管道提前结束的情况
+func RunCSVPipeline[R any](
+	ctx context.Context,
+	filePath string,
+	opts PipelineOptions,
+	process func(ctx context.Context, row PipelineRow) (R, error),
+	sink func(row PipelineRow, result R) error,
+) (PipelineResult, error) {
+	var result PipelineResult
+	if process == nil {
+		return result, errors.New("process function cannot be nil")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts = normalizePipelineOptions(opts)
+	opts.Read.Logger = liblog.Or(opts.Read.Logger, liblog.FromContext(ctx, pkgLogger.Get()))
+
+	// 读取失败的记录由生产者协程收集，管道结束后合并
+	var readErrors []*RowError
+	handler := opts.Read.OnRowError
+	opts.Read.OnRowError = func(line int, record []string, err error) error {
+		readErrors = append(readErrors, &RowError{Line: line, Record: record, Err: err})
+		if handler != nil {
+			return handler(line, record, err)
+		}
+		return nil
+	}
+
+	src, err := openCSVSource(filePath, opts.Read)
+	if err != nil {
+		return result, fmt.Errorf("read header failed: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan PipelineRow, opts.BufferSize)
+	results := make(chan pipelineItem[R], opts.BufferSize)
+	// 令牌限制已读取但尚未交付的记录数，避免有序模式下等待排序的结果无限堆积
+	tokens := make(chan struct{}, opts.Workers+opts.BufferSize)
+
+	// 1. 生产者：逐行读取并分发记录，结束时关闭任务通道
+	var summary ReadSummary
+	var produceErr error
+	go func() {
+		defer close(jobs)
+		defer src.Close()
+		for index := 0; ; index++ {
+			dict, err := src.nextDict(&summary)
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					produceErr = err
+				}
+				return
+			}
+			select {
+			case tokens <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- PipelineRow{Index: index, Line: src.Line(), Data: dict}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// 2. 工作池：处理记录，全部退出后关闭结果通道
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				if ctx.Err() != nil {
+					continue // 已取消：丢弃剩余记录，等待生产者退出
+				}
+				value, err := process(ctx, row)
+				results <- pipelineItem[R]{row: row, value: value, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 3. 收集结果并交给 sink，有序模式下缓存先完成的结果直到轮到它
+	var processErrors []*RowError
+	var stopErr error
+	deliver := func(item pipelineItem[R]) {
+		defer func() { <-tokens }()
+		if stopErr != nil {
+			return
+		}
+		if item.err != nil {
+			rowErr := &RowError{Line: item.row.Line, Err: item.err}
+			processErrors = append(processErrors, rowErr)
+			if opts.StopOnError {
+				stopErr = fmt.Errorf("pipeline stopped: %w", rowErr)
+				cancel()
+			}
+			return
+		}
+		if sink != nil {
+			if err := sink(item.row, item.value); err != nil {
+				stopErr = fmt.Errorf("sink failed at row %d: %w", item.row.Line, err)
+				cancel()
+				return
+			}
+		}
+		result.Processed++
+	}
+
+	pending := make(map[int]pipelineItem[R])
+	next := 0
+	for item := range results {
+		if !opts.Ordered {
+			deliver(item)
+			continue
+		}
+		pending[item.row.Index] = item
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			deliver(ready)
+			next++
+		}
+	}
+
+	// 结果通道关闭时生产者已退出，可以安全读取其状态
+	result.Summary = summary
+	result.Errors = append(readErrors, processErrors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Line < result.Errors[j].Line
+	})
+
+	switch {
+	case stopErr != nil:
+		return result, stopErr
+	case produceErr != nil:
+		return result, fmt.Errorf("producer read csv failed: %w", produceErr)
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// TransformCSVFile 并行转换 CSV 文件并按原始顺序写入 outPath（覆盖写入），transform 返回输出行，
+// header 非空时先写入表头。处理失败的记录不写入输出文件，收集在 PipelineResult.Errors 中
+func TransformCSVFile(
+	ctx context.Context,
+	inPath string,
+	outPath string,
+	header []string,
+	opts PipelineOptions,
+	writeOpts WriteOptions,
+	transform func(ctx context.Context, row PipelineRow) ([]string, error),
+) (result PipelineResult, err error) {
+	writer, err := openCSVWriter(outPath, true, writeOpts)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if cErr := writer.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf("csv writer flush error for %s: %w", outPath, cErr)
+		}
+	}()
+
+	if len(header) > 0 {
+		if err := writer.Write(header); err != nil {
+			return result, fmt.Errorf("write header to %s failed: %w", outPath, err)
+		}
+	}
+
+	opts.Ordered = true
+	return RunCSVPipeline(ctx, inPath, opts, transform, func(_ PipelineRow, row []string) error {
+		return writer.Write(row)
+	})
+}
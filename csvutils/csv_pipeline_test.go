@@ -0,0 +1,117 @@
+package csvutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// makePipelineCSV 生成 n 行 id,name 数据
+func makePipelineCSV(t *testing.T, n int) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d,name%d\n", i, i)
+	}
+	return makeTempCSV(t, "pipeline.csv", b.String())
+}
+
+// TestTransformCSVFile 测试并行转换按原始顺序写出，并收集带行号的处理错误
+func TestTransformCSVFile(t *testing.T) {
+	inPath := makePipelineCSV(t, 50)
+	outPath := filepath.Join(t.TempDir(), "out.csv")
+
+	opts := DefaultPipelineOptions()
+	opts.Workers = 8
+	opts.BufferSize = 4
+	result, err := TransformCSVFile(context.Background(), inPath, outPath, []string{"id", "upper"}, opts, DefaultWriteOptions(),
+		func(_ context.Context, row PipelineRow) ([]string, error) {
+			id := row.Data["id"].(string)
+			if id == "7" || id == "30" {
+				return nil, errors.New("rejected")
+			}
+			// 越靠前的记录处理越慢，检验输出顺序
+			time.Sleep(time.Duration(50-row.Index) * 50 * time.Microsecond)
+			return []string{id, strings.ToUpper(row.Data["name"].(string))}, nil
+		})
+	if err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	if result.Processed != 48 || result.Summary.Read != 50 || len(result.Errors) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Errors[0].Line != 8 || result.Errors[1].Line != 31 {
+		t.Errorf("unexpected error lines: %v", result.Err())
+	}
+
+	_, rows, err := ReadCSV2Rows(outPath, ',', true)
+	if err != nil || len(rows) != 48 {
+		t.Fatalf("read output failed: %v, %d rows", err, len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], []string{"1", "NAME1"}) || !reflect.DeepEqual(rows[6], []string{"8", "NAME8"}) || !reflect.DeepEqual(rows[47], []string{"50", "NAME50"}) {
+		t.Errorf("output out of order: %v %v %v", rows[0], rows[6], rows[47])
+	}
+}
+
+// TestRunCSVPipelineCancel 测试 StopOnError 与 ctx 取消时管道提前结束
+func TestRunCSVPipelineCancel(t *testing.T) {
+	inPath := makePipelineCSV(t, 1000)
+
+	opts := DefaultPipelineOptions()
+	opts.Workers = 2
+	opts.StopOnError = true
+	var processed int32
+	_, err := RunCSVPipeline(context.Background(), inPath, opts, func(_ context.Context, row PipelineRow) (int, error) {
+		atomic.AddInt32(&processed, 1)
+		if row.Index == 10 {
+			return 0, errors.New("boom")
+		}
+		return row.Index, nil
+	}, nil)
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 12 {
+		t.Errorf("expected stop at row 12, got %v", err)
+	}
+	if n := atomic.LoadInt32(&processed); n >= 1000 {
+		t.Errorf("pipeline not stopped early, processed %d", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts.StopOnError = false
+	_, err = RunCSVPipeline(ctx, inPath, opts, func(_ context.Context, row PipelineRow) (int, error) {
+		if row.Index == 5 {
+			cancel()
+		}
+		return row.Index, nil
+	}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+// TestReadBigCSVWithConsumers 测试消费者处理全部记录并返回全部行错误
+func TestReadBigCSVWithConsumers(t *testing.T) {
+	inPath := makePipelineCSV(t, 200)
+
+	var count int32
+	err := ReadBigCSVWithConsumers(context.Background(), inPath, ',', true, true, 4, 0, func(m map[string]interface{}) error {
+		atomic.AddInt32(&count, 1)
+		if id := m["id"].(int64); id%100 == 0 {
+			return fmt.Errorf("bad id %d", id)
+		}
+		return nil
+	})
+	if atomic.LoadInt32(&count) != 200 {
+		t.Errorf("expected 200 rows consumed, got %d", count)
+	}
+	if err == nil || !strings.Contains(err.Error(), "row 101: bad id 100") || !strings.Contains(err.Error(), "row 201: bad id 200") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
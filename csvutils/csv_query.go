@@ -0,0 +1,628 @@
+package csvutils
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RecordWriter 接收查询结果的 CSV 写入器，*csv.Writer 与 csvwriter.CSVWriter 均满足该接口
+type RecordWriter interface {
+	Write(record []string) error
+}
+
+// QueryRow 查询过滤条件接收的一行数据
+type QueryRow struct {
+	Line    int      // 记录起始物理行号
+	Record  []string // 原始记录
+	columns map[string]int
+}
+
+// Get 按列名获取字段值，列不存在或记录缺少该字段时返回空串
+func (r QueryRow) Get(col string) string {
+	if i, ok := r.columns[col]; ok && i < len(r.Record) {
+		return r.Record[i]
+	}
+	return ""
+}
+
+// Query 流式 CSV 查询，按 Where -> DistinctBy -> SortBy -> Limit -> Select 的顺序处理，
+// 未排序时逐行输出，排序时超过内存块大小的数据写入临时文件后归并
+type Query struct {
+	filePath  string
+	opts      ReadOptions
+	columns   []string
+	filters   []func(QueryRow) bool
+	whereCols []string // WhereExpr 引用的列，执行时校验是否存在于表头
+	distinct  []string
+	sortKeys  []sortKey
+	limit     int
+	chunkRows int
+	tempDir   string
+	err       error
+}
+
+// sortKey 排序列
+type sortKey struct {
+	col  string
+	desc bool
+}
+
+// NewQuery 创建 CSV 查询
+func NewQuery(filePath string) *Query {
+	return NewQueryWithOptions(filePath, DefaultReadOptions())
+}
+
+// NewQueryWithOptions 按读取选项创建 CSV 查询
+func NewQueryWithOptions(filePath string, opts ReadOptions) *Query {
+	return &Query{filePath: filePath, opts: opts, limit: -1, chunkRows: 100000}
+}
+
+// Select 指定输出列及顺序，未调用时输出全部列
+func (q *Query) Select(cols ...string) *Query {
+	q.columns = cols
+	return q
+}
+
+// Where 添加过滤条件，多次调用时需同时满足
+func (q *Query) Where(predicate func(row QueryRow) bool) *Query {
+	if predicate != nil {
+		q.filters = append(q.filters, predicate)
+	}
+	return q
+}
+
+// WhereExpr 以表达式添加过滤条件，如 `age >= 18 && city == "北京"`。
+// 支持 ==、!=、>、>=、<、<=、contains 运算符与 && 连接，两侧均为数字时按数值比较，均不是数字时按字符串比较，
+// 只有一侧是数字时（如空值或 N/A）只满足 != 条件；值中包含 && 或运算符时需用双引号包裹
+func (q *Query) WhereExpr(expr string) *Query {
+	predicate, cols, err := parseWhereExpr(expr)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.whereCols = append(q.whereCols, cols...)
+	return q.Where(predicate)
+}
+
+// DistinctBy 按指定列去重，保留首次出现的记录，未指定列时按整行去重。已出现的键保存在内存中
+func (q *Query) DistinctBy(keys ...string) *Query {
+	if keys == nil {
+		keys = []string{}
+	}
+	q.distinct = keys
+	return q
+}
+
+// SortBy 按指定列排序，列名前加 "-" 表示降序，值相同时保持原有顺序
+func (q *Query) SortBy(cols ...string) *Query {
+	q.sortKeys = nil
+	for _, col := range cols {
+		key := sortKey{col: col}
+		if strings.HasPrefix(col, "-") {
+			key = sortKey{col: col[1:], desc: true}
+		}
+		q.sortKeys = append(q.sortKeys, key)
+	}
+	return q
+}
+
+// SortMemory 设置排序时内存中保存的最大行数及临时文件目录，超出时分块排序写入临时文件后归并，
+// rows <= 0 时为 100000，dir 为空时使用系统临时目录
+func (q *Query) SortMemory(rows int, dir string) *Query {
+	if rows <= 0 {
+		rows = 100000
+	}
+	q.chunkRows = rows
+	q.tempDir = dir
+	return q
+}
+
+// Limit 限制输出行数，n < 0 表示不限制
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Each 执行查询，将结果列名交给 onHeader（可为 nil），结果行依次交给 onRow
+func (q *Query) Each(onHeader func(header []string) error, onRow func(record []string) error) error {
+	if q.err != nil {
+		return q.err
+	}
+	if onRow == nil {
+		return errors.New("row callback cannot be nil")
+	}
+
+	src, err := openCSVSource(q.filePath, q.opts)
+	if err != nil {
+		return fmt.Errorf("read header failed: %w", err)
+	}
+	defer src.Close()
+
+	header := src.Header()
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		columns[col] = i
+	}
+	projection, err := GetKeyIndices(header, q.columns)
+	if err != nil {
+		return fmt.Errorf("select columns failed: %w", err)
+	}
+	if _, err := GetKeyIndices(header, q.whereCols); err != nil {
+		return fmt.Errorf("where columns failed: %w", err)
+	}
+	if onHeader != nil {
+		if err := onHeader(pickFields(header, projection)); err != nil {
+			return err
+		}
+	}
+
+	// 输出阶段：限制行数并投影
+	written := 0
+	output := func(record []string) error {
+		if q.limit >= 0 && written >= q.limit {
+			return errQueryDone
+		}
+		written++
+		return onRow(pickFields(record, projection))
+	}
+
+	// 排序阶段：收集全部记录排序后输出
+	emit := output
+	var sorter *externalSorter
+	if len(q.sortKeys) > 0 {
+		keyIndices := make([]int, len(q.sortKeys))
+		for i, key := range q.sortKeys {
+			idx, ok := columns[key.col]
+			if !ok {
+				return fmt.Errorf("sort column '%s' not found in header", key.col)
+			}
+			keyIndices[i] = idx
+		}
+		sorter = newExternalSorter(q.sortKeys, keyIndices, q.chunkRows, q.tempDir)
+		defer sorter.cleanup()
+		emit = sorter.add
+	}
+
+	// 去重阶段
+	var distinctIdx []int
+	var seen map[string]struct{}
+	if q.distinct != nil {
+		if distinctIdx, err = GetKeyIndices(header, q.distinct); err != nil {
+			return fmt.Errorf("distinct columns failed: %w", err)
+		}
+		seen = make(map[string]struct{})
+	}
+
+	var summary ReadSummary
+	for {
+		record, err := src.nextRecord(&summary)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		row := QueryRow{Line: src.Line(), Record: record, columns: columns}
+		if !matchAll(q.filters, row) {
+			continue
+		}
+		if seen != nil {
+			key := strings.Join(pickFields(record, distinctIdx), "\x00")
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		if err := emit(record); err != nil {
+			if errors.Is(err, errQueryDone) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if sorter != nil {
+		if err := sorter.drain(output); err != nil && !errors.Is(err, errQueryDone) {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV 执行查询并将结果写入 w，withHeader 为 true 时先写入列名，返回写入的数据行数。
+// w 为 *csv.Writer 时需调用方自行 Flush
+func (q *Query) WriteCSV(w RecordWriter, withHeader bool) (int, error) {
+	count := 0
+	var onHeader func([]string) error
+	if withHeader {
+		onHeader = w.Write
+	}
+	err := q.Each(onHeader, func(record []string) error {
+		count++
+		return w.Write(record)
+	})
+	return count, err
+}
+
+// WriteFile 执行查询并将结果连同列名写入 CSV 文件，返回写入的数据行数
+func (q *Query) WriteFile(filePath string, opts WriteOptions) (count int, err error) {
+	writer, err := openCSVWriter(filePath, true, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cErr := writer.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf("csv writer flush error for %s: %w", filePath, cErr)
+		}
+	}()
+	return q.WriteCSV(writer, true)
+}
+
+// errQueryDone 达到 Limit 后提前结束查询
+var errQueryDone = errors.New("query done")
+
+// matchAll 判断记录是否满足全部过滤条件
+func matchAll(filters []func(QueryRow) bool, row QueryRow) bool {
+	for _, filter := range filters {
+		if !filter(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickFields 按列索引提取字段，记录缺少的字段为空串
+func pickFields(record []string, indices []int) []string {
+	fields := make([]string, len(indices))
+	for i, idx := range indices {
+		if idx < len(record) {
+			fields[i] = record[idx]
+		}
+	}
+	return fields
+}
+
+// compareValues 比较两个字段值，数字按数值比较、字符串按字典序比较，数字始终排在字符串之前，保证排序使用的是全序
+func compareValues(a, b string) int {
+	fa, numA := parseNumber(a)
+	fb, numB := parseNumber(b)
+	switch {
+	case numA && numB:
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	case numA:
+		return -1
+	case numB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareFilter 过滤条件使用的比较，两侧均为数字时按数值比较，均不是数字时按字符串比较，
+// 只有一侧是数字时无法比较，返回 false
+func compareFilter(a, b string) (int, bool) {
+	_, numA := parseNumber(a)
+	_, numB := parseNumber(b)
+	if numA != numB {
+		return 0, false
+	}
+	return compareValues(a, b), true
+}
+
+// parseNumber 将字段值解析为数字，NaN 无法参与比较，按字符串处理
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// whereOperators 表达式支持的运算符，同一位置较长的运算符优先匹配
+var whereOperators = []string{">=", "<=", "!=", "==", ">", "<", " contains "}
+
+// parseWhereExpr 将 && 连接的比较表达式解析为过滤条件，并返回表达式引用的列名，
+// 双引号内的 && 与运算符按普通字符处理
+func parseWhereExpr(expr string) (func(QueryRow) bool, []string, error) {
+	parts, err := splitWhereExpr(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid where expression '%s': %w", expr, err)
+	}
+	var conditions []func(QueryRow) bool
+	var cols []string
+	for _, part := range parts {
+		condition, col, err := parseWhereCondition(strings.TrimSpace(part))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid where expression '%s': %w", expr, err)
+		}
+		conditions = append(conditions, condition)
+		cols = append(cols, col)
+	}
+	return func(row QueryRow) bool {
+		return matchAll(conditions, row)
+	}, cols, nil
+}
+
+// splitWhereExpr 按双引号外的 && 拆分表达式，引号未闭合时返回错误
+func splitWhereExpr(expr string) ([]string, error) {
+	var parts []string
+	start, inQuote := 0, false
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case inQuote && expr[i] == '\\':
+			i++ // 跳过转义字符
+		case expr[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(expr[i:], "&&"):
+			parts = append(parts, expr[start:i])
+			start = i + 2
+			i++
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quote")
+	}
+	return append(parts, expr[start:]), nil
+}
+
+// findWhereOperator 返回双引号外第一个运算符的位置和运算符，未找到时返回 -1
+func findWhereOperator(cond string) (int, string) {
+	inQuote := false
+	for i := 0; i < len(cond); i++ {
+		switch {
+		case inQuote && cond[i] == '\\':
+			i++
+		case cond[i] == '"':
+			inQuote = !inQuote
+		case !inQuote:
+			for _, op := range whereOperators {
+				if strings.HasPrefix(cond[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parseWhereCondition 解析单个 "列 运算符 值" 形式的条件并返回列名，值可用双引号包裹
+func parseWhereCondition(cond string) (func(QueryRow) bool, string, error) {
+	idx, op := findWhereOperator(cond)
+	if idx <= 0 {
+		return nil, "", fmt.Errorf("no operator in condition '%s'", cond)
+	}
+	col := strings.TrimSpace(cond[:idx])
+	value := strings.TrimSpace(cond[idx+len(op):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid quoted value in condition '%s'", cond)
+		}
+		value = unquoted
+	}
+	var match func(c int) bool
+	switch strings.TrimSpace(op) {
+	case "contains":
+		return func(r QueryRow) bool { return strings.Contains(r.Get(col), value) }, col, nil
+	case "!=":
+		return func(r QueryRow) bool {
+			c, ok := compareFilter(r.Get(col), value)
+			return !ok || c != 0
+		}, col, nil
+	case "==":
+		match = func(c int) bool { return c == 0 }
+	case ">":
+		match = func(c int) bool { return c > 0 }
+	case ">=":
+		match = func(c int) bool { return c >= 0 }
+	case "<":
+		match = func(c int) bool { return c < 0 }
+	default:
+		match = func(c int) bool { return c <= 0 }
+	}
+	return func(r QueryRow) bool {
+		c, ok := compareFilter(r.Get(col), value)
+		return ok && match(c)
+	}, col, nil
+}
+
+// externalSorter 外部归并排序：内存中的记录达到块大小时排序后写入临时文件，最后多路归并输出
+type externalSorter struct {
+	keys      []sortKey
+	indices   []int
+	chunkRows int
+	tempDir   string
+	dir       string // 本次排序创建的临时目录
+	buffer    [][]string
+	chunks    []string // 已写入的有序块文件
+}
+
+func newExternalSorter(keys []sortKey, indices []int, chunkRows int, tempDir string) *externalSorter {
+	return &externalSorter{keys: keys, indices: indices, chunkRows: chunkRows, tempDir: tempDir}
+}
+
+// less 按排序列比较两条记录
+func (s *externalSorter) less(a, b []string) bool {
+	for i, key := range s.keys {
+		var av, bv string
+		if idx := s.indices[i]; idx < len(a) {
+			av = a[idx]
+		}
+		if idx := s.indices[i]; idx < len(b) {
+			bv = b[idx]
+		}
+		c := compareValues(av, bv)
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+func (s *externalSorter) add(record []string) error {
+	s.buffer = append(s.buffer, record)
+	if len(s.buffer) >= s.chunkRows {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush 将内存中的记录排序后以 gob 编码写入临时块文件，只含一个空字段的记录也能原样读回
+func (s *externalSorter) flush() error {
+	sort.SliceStable(s.buffer, func(i, j int) bool { return s.less(s.buffer[i], s.buffer[j]) })
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.tempDir, "csvquery-*")
+		if err != nil {
+			return fmt.Errorf("create sort temp dir failed: %w", err)
+		}
+		s.dir = dir
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("chunk-%d.gob", len(s.chunks)))
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create sort chunk failed: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	encoder := gob.NewEncoder(writer)
+	for _, record := range s.buffer {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return fmt.Errorf("write sort chunk failed: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("write sort chunk failed: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close sort chunk failed: %w", err)
+	}
+	s.chunks = append(s.chunks, path)
+	s.buffer = s.buffer[:0]
+	return nil
+}
+
+// drain 按顺序输出全部记录，数据未超过块大小时直接在内存中排序
+func (s *externalSorter) drain(output func([]string) error) error {
+	if len(s.chunks) == 0 {
+		sort.SliceStable(s.buffer, func(i, j int) bool { return s.less(s.buffer[i], s.buffer[j]) })
+		for _, record := range s.buffer {
+			if err := output(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(s.buffer) > 0 {
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+
+	// 多路归并，值相同时块序号小的在前，保持排序稳定
+	h := &mergeHeap{sorter: s}
+	for i, path := range s.chunks {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open sort chunk failed: %w", err)
+		}
+		defer file.Close()
+		item := &mergeItem{decoder: gob.NewDecoder(bufio.NewReader(file)), chunk: i}
+		if err := item.next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				continue
+			}
+			return err
+		}
+		h.items = append(h.items, item)
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		item := h.items[0]
+		if err := output(item.record); err != nil {
+			return err
+		}
+		if err := item.next(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			heap.Pop(h)
+			continue
+		}
+		heap.Fix(h, 0)
+	}
+	return nil
+}
+
+// cleanup 删除临时块文件
+func (s *externalSorter) cleanup() {
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// mergeItem 归并中一个块的当前记录
+type mergeItem struct {
+	decoder *gob.Decoder
+	record  []string
+	chunk   int
+}
+
+func (m *mergeItem) next() error {
+	var record []string
+	if err := m.decoder.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		return fmt.Errorf("read sort chunk failed: %w", err)
+	}
+	m.record = record
+	return nil
+}
+
+// mergeHeap 按排序列组织各块当前记录的小顶堆
+type mergeHeap struct {
+	items  []*mergeItem
+	sorter *externalSorter
+}
+
+func (h *mergeHeap) Len() int { return len(h.items) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.sorter.less(a.record, b.record) {
+		return true
+	}
+	if h.sorter.less(b.record, a.record) {
+		return false
+	}
+	return a.chunk < b.chunk
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap) Push(x interface{}) { h.items = append(h.items, x.(*mergeItem)) }
+
+func (h *mergeHeap) Pop() interface{} {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
@@ -0,0 +1,161 @@
+package csvutils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestQuery 测试过滤、去重、排序、限制行数与投影的组合
+func TestQuery(t *testing.T) {
+	content := "name,city,age\n张三,北京,30\n李四,上海,25\n王五,北京,17\n张三,北京,30\n赵六,广州,41\n钱七,北京,25\n"
+	filePath := makeTempCSV(t, "people.csv", content)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	count, err := NewQuery(filePath).
+		WhereExpr("age >= 18").
+		Where(func(row QueryRow) bool { return row.Get("city") != "广州" }).
+		DistinctBy("name", "city").
+		SortBy("-age", "name").
+		Limit(3).
+		Select("name", "age").
+		WriteCSV(w, true)
+	w.Flush()
+	if err != nil || count != 3 {
+		t.Fatalf("query failed: %v, %d rows", err, count)
+	}
+	expected := "name,age\n张三,30\n李四,25\n钱七,25\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	if _, err := NewQuery(filePath).Select("missing").WriteCSV(csv.NewWriter(&buf), false); err == nil {
+		t.Error("expected missing column error")
+	}
+	if _, err := NewQuery(filePath).WhereExpr("age").WriteCSV(csv.NewWriter(&buf), false); err == nil {
+		t.Error("expected invalid expression error")
+	}
+	if _, err := NewQuery(filePath).WhereExpr("agee >= 18").WriteCSV(csv.NewWriter(&buf), false); err == nil {
+		t.Error("expected unknown where column error")
+	}
+}
+
+// TestQueryExternalSort 测试超过内存块大小时的外部归并排序
+func TestQueryExternalSort(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,group\n")
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&b, "%d,%d\n", (i*37)%500, i%3)
+	}
+	filePath := makeTempCSV(t, "sort.csv", b.String())
+	outPath := filepath.Join(t.TempDir(), "sorted.csv")
+	tempDir := t.TempDir()
+
+	count, err := NewQuery(filePath).SortBy("group", "-id").SortMemory(64, tempDir).WriteFile(outPath, DefaultWriteOptions())
+	if err != nil || count != 500 {
+		t.Fatalf("sort failed: %v, %d rows", err, count)
+	}
+	header, rows, err := ReadCSV2Rows(outPath, ',', true)
+	if err != nil || !reflect.DeepEqual(header, []string{"id", "group"}) || len(rows) != 500 {
+		t.Fatalf("read sorted output failed: %v", err)
+	}
+	for i := 1; i < len(rows); i++ {
+		prev, cur := rows[i-1], rows[i]
+		if prev[1] > cur[1] || (prev[1] == cur[1] && compareValues(prev[0], cur[0]) < 0) {
+			t.Fatalf("rows %d and %d out of order: %v %v", i-1, i, prev, cur)
+		}
+	}
+	if entries, _ := filepath.Glob(filepath.Join(tempDir, "*")); len(entries) != 0 {
+		t.Errorf("temp files not removed: %v", entries)
+	}
+}
+
+// TestCompareValuesTotalOrder 测试数字与字符串混合时比较结果满足传递性
+func TestCompareValuesTotalOrder(t *testing.T) {
+	values := []string{"10", "9", "a", "10a", "", "NaN", "-1"}
+	sort.Slice(values, func(i, j int) bool { return compareValues(values[i], values[j]) < 0 })
+	expected := []string{"-1", "9", "10", "", "10a", "NaN", "a"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected order: %q", values)
+	}
+	for _, a := range values {
+		for _, b := range values {
+			for _, c := range values {
+				if compareValues(a, b) < 0 && compareValues(b, c) < 0 && compareValues(a, c) >= 0 {
+					t.Errorf("order not transitive: %q < %q < %q", a, b, c)
+				}
+			}
+		}
+	}
+}
+
+// TestWhereExprQuotes 测试双引号内的 && 与运算符按普通字符处理
+func TestWhereExprQuotes(t *testing.T) {
+	filePath := makeTempCSV(t, "where_quotes.csv", "name,note\na,x && y\nb,a>=b\nc,plain\n")
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_, err := NewQuery(filePath).WhereExpr(`note == "x && y" && name != "b"`).Select("name").WriteCSV(w, false)
+	w.Flush()
+	if err != nil || buf.String() != "a\n" {
+		t.Errorf("unexpected && in quotes result: %q, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	w = csv.NewWriter(&buf)
+	_, err = NewQuery(filePath).WhereExpr(`note contains ">="`).Select("name").WriteCSV(w, false)
+	w.Flush()
+	if err != nil || buf.String() != "b\n" {
+		t.Errorf("unexpected operator in quotes result: %q, %v", buf.String(), err)
+	}
+
+	for _, expr := range []string{`note == "x && y`, `note == "a" b`, `name == a &&`} {
+		if _, err := NewQuery(filePath).WhereExpr(expr).WriteCSV(csv.NewWriter(&buf), false); err == nil {
+			t.Errorf("expected error for expression %s", expr)
+		}
+	}
+}
+
+// TestWhereExprNonNumeric 测试数值比较条件不匹配空值与非数字字段
+func TestWhereExprNonNumeric(t *testing.T) {
+	filePath := makeTempCSV(t, "where_non_numeric.csv", "name,age\na,20\nb,\nc,N/A\nd,10\n")
+	cases := map[string]string{
+		"age >= 18":    "a\n",
+		"age > 18":     "a\n",
+		"age < 18":     "d\n",
+		"age == 10":    "d\n",
+		"age != 10":    "a\nb\nc\n",
+		`age == "N/A"`: "c\n",
+		`age > "M"`:    "c\n",
+	}
+	for expr, expected := range cases {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_, err := NewQuery(filePath).WhereExpr(expr).Select("name").WriteCSV(w, false)
+		w.Flush()
+		if err != nil || buf.String() != expected {
+			t.Errorf("%s: unexpected result %q, %v", expr, buf.String(), err)
+		}
+	}
+}
+
+// TestQueryExternalSortEmptyField 测试只含一个空字段的记录在外部排序后不会丢失
+func TestQueryExternalSortEmptyField(t *testing.T) {
+	filePath := makeTempCSV(t, "sort_empty.csv", "name\nc\n\"\"\na\n\"\"\nb\n")
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	count, err := NewQuery(filePath).SortBy("name").SortMemory(2, t.TempDir()).WriteCSV(w, false)
+	w.Flush()
+	if err != nil || count != 5 {
+		t.Fatalf("query failed: %v, %d rows", err, count)
+	}
+	if buf.String() != "\n\na\nb\nc\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
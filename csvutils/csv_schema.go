@@ -0,0 +1,310 @@
+package csvutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winezer0/xutils/timeutils"
+)
+
+// ColumnType 列数据类型
+type ColumnType int
+
+const (
+	ColumnString ColumnType = iota // 字符串，无法统一为其他类型的列
+	ColumnInt                      // 整数，转换为 int64
+	ColumnFloat                    // 浮点数，转换为 float64
+	ColumnBool                     // 布尔值 true/false，转换为 bool
+	ColumnTime                     // 时间，按 Layout 转换为 time.Time
+)
+
+var columnTypeNames = [...]string{"string", "int", "float", "bool", "time"}
+
+// String 返回类型名称
+func (t ColumnType) String() string {
+	if t < ColumnString || int(t) >= len(columnTypeNames) {
+		return fmt.Sprintf("ColumnType(%d)", int(t))
+	}
+	return columnTypeNames[t]
+}
+
+// TimeLayouts 推断时间列时依次尝试的布局，复用 timeutils 中的布局定义
+var TimeLayouts = []string{
+	timeutils.LayoutDateTime,
+	timeutils.LayoutDate,
+	timeutils.LayoutISO8601,
+	timeutils.LayoutFileSafe,
+	timeutils.LayoutCN,
+}
+
+// ColumnSchema 单列的类型定义
+type ColumnSchema struct {
+	Name     string     // 列名
+	Type     ColumnType // 列类型
+	Nullable bool       // 是否允许空值，空值统一转换为 nil
+	Layout   string     // 时间列的布局，为空时依次尝试 TimeLayouts
+}
+
+// Convert 将字段值转换为列类型对应的 Go 类型。
+// 空值在非字符串列或可空列中转换为 nil，非空且不符合列类型的值返回错误
+func (c ColumnSchema) Convert(val string) (interface{}, error) {
+	if val == "" {
+		if c.Type == ColumnString && !c.Nullable {
+			return "", nil
+		}
+		return nil, nil
+	}
+	switch c.Type {
+	case ColumnInt:
+		i, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: invalid int '%s'", c.Name, val)
+		}
+		return i, nil
+	case ColumnFloat:
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: invalid float '%s'", c.Name, val)
+		}
+		return f, nil
+	case ColumnBool:
+		b, ok := parseBoolValue(val)
+		if !ok {
+			return nil, fmt.Errorf("column %s: invalid bool '%s'", c.Name, val)
+		}
+		return b, nil
+	case ColumnTime:
+		if c.Layout != "" {
+			t, err := time.Parse(c.Layout, val)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: invalid time '%s' for layout '%s'", c.Name, val, c.Layout)
+			}
+			return t, nil
+		}
+		layout := detectTimeLayout(val)
+		if layout == "" {
+			return nil, fmt.Errorf("column %s: invalid time '%s'", c.Name, val)
+		}
+		t, _ := time.Parse(layout, val)
+		return t, nil
+	default:
+		return val, nil
+	}
+}
+
+// CSVSchema CSV 文件的列类型定义，列按名称匹配
+type CSVSchema struct {
+	Columns []ColumnSchema
+}
+
+// Column 获取指定列的定义
+func (s *CSVSchema) Column(name string) (ColumnSchema, bool) {
+	if s == nil {
+		return ColumnSchema{}, false
+	}
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return ColumnSchema{}, false
+}
+
+// Set 覆盖同名列的定义，不存在时追加
+func (s *CSVSchema) Set(col ColumnSchema) {
+	for i := range s.Columns {
+		if s.Columns[i].Name == col.Name {
+			s.Columns[i] = col
+			return
+		}
+	}
+	s.Columns = append(s.Columns, col)
+}
+
+// InferCSVSchema 采样前 sampleRows 行数据推断每列的类型，sampleRows <= 0 时采样全部数据
+func InferCSVSchema(filePath string, sampleRows int) (*CSVSchema, error) {
+	return InferCSVSchemaWithOptions(filePath, sampleRows, DefaultReadOptions())
+}
+
+// InferCSVSchemaWithOptions 按读取选项采样推断每列的类型。
+// 一列中所有非空值均能解析时才采用 int、float、bool、time 类型（按此优先级），否则为 string；
+// 样本中出现空值或缺失字段的列标记为可空，opts.ColumnTypes 中的定义覆盖推断结果
+func InferCSVSchemaWithOptions(filePath string, sampleRows int, opts ReadOptions) (*CSVSchema, error) {
+	src, err := openCSVSource(filePath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("read header failed: %w", err)
+	}
+	defer src.Close()
+
+	header := src.Header()
+	stats := make([]columnStats, len(header))
+	for i := range stats {
+		stats[i] = newColumnStats()
+	}
+	for count := 0; sampleRows <= 0 || count < sampleRows; {
+		row, err := src.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if !isRowError(err) {
+				return nil, fmt.Errorf("read row %d failed: %w", src.Line(), err)
+			}
+			src.opts.Logger.Warnf("skip row %d, read error: %v", src.Line(), err)
+			continue
+		}
+		count++
+		for i := range stats {
+			if i < len(row) {
+				stats[i].observe(row[i])
+			} else {
+				stats[i].nullable = true
+			}
+		}
+	}
+
+	schema := &CSVSchema{Columns: make([]ColumnSchema, len(header))}
+	for i, name := range header {
+		schema.Columns[i] = stats[i].schema(name)
+	}
+	for name, col := range src.opts.ColumnTypes {
+		col.Name = name
+		schema.Set(col)
+	}
+	return schema, nil
+}
+
+// columnStats 推断过程中单列的候选类型
+type columnStats struct {
+	isInt, isFloat, isBool, isTime bool
+	layout                         string
+	values                         int
+	nullable                       bool
+}
+
+func newColumnStats() columnStats {
+	return columnStats{isInt: true, isFloat: true, isBool: true, isTime: true}
+}
+
+// observe 根据一个字段值排除不可能的类型
+func (s *columnStats) observe(val string) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		s.nullable = true
+		return
+	}
+	s.values++
+	if s.isInt {
+		_, err := strconv.ParseInt(val, 10, 64)
+		s.isInt = err == nil
+	}
+	if s.isFloat {
+		// 排除 NaN、Inf 等不含数字的文本
+		_, err := strconv.ParseFloat(val, 64)
+		s.isFloat = err == nil && strings.ContainsAny(val, "0123456789")
+	}
+	if s.isBool {
+		_, s.isBool = parseBoolValue(val)
+	}
+	if s.isTime {
+		// 同一列的时间值必须使用相同的布局
+		if s.layout == "" {
+			s.layout = detectTimeLayout(val)
+			s.isTime = s.layout != ""
+		} else if _, err := time.Parse(s.layout, val); err != nil {
+			s.isTime = false
+		}
+	}
+}
+
+// schema 根据剩余的候选类型生成列定义，没有非空值的列视为可空字符串
+func (s *columnStats) schema(name string) ColumnSchema {
+	col := ColumnSchema{Name: name, Type: ColumnString, Nullable: s.nullable}
+	switch {
+	case s.values == 0:
+		col.Nullable = true
+	case s.isInt:
+		col.Type = ColumnInt
+	case s.isFloat:
+		col.Type = ColumnFloat
+	case s.isBool:
+		col.Type = ColumnBool
+	case s.isTime:
+		col.Type = ColumnTime
+		col.Layout = s.layout
+	}
+	return col
+}
+
+// parseBoolValue 解析 true/false（不区分大小写），与 convertActualType 保持一致不接受 1/0
+func parseBoolValue(val string) (bool, bool) {
+	switch {
+	case strings.EqualFold(val, "true"):
+		return true, true
+	case strings.EqualFold(val, "false"):
+		return false, true
+	}
+	return false, false
+}
+
+// detectTimeLayout 返回第一个能解析该值的 TimeLayouts 布局，均不匹配时返回空串
+func detectTimeLayout(val string) string {
+	for _, layout := range TimeLayouts {
+		if _, err := time.Parse(layout, val); err == nil {
+			return layout
+		}
+	}
+	return ""
+}
+
+// resolveColumns 根据 Schema 与 ColumnTypes 确定每列的定义，没有定义的列为 nil，均未设置时返回 nil
+func resolveColumns(header []string, opts ReadOptions) []*ColumnSchema {
+	if opts.Schema == nil && len(opts.ColumnTypes) == 0 {
+		return nil
+	}
+	columns := make([]*ColumnSchema, len(header))
+	for i, name := range header {
+		col, ok := opts.ColumnTypes[name]
+		if !ok {
+			col, ok = opts.Schema.Column(name)
+		}
+		if ok {
+			col.Name = name
+			columns[i] = &col
+		}
+	}
+	return columns
+}
+
+// rowToDict 将行数据转换为字典，定义了列类型的列按类型转换，其余列按 ConvertType 处理
+func (s *csvSource) rowToDict(row []string) (map[string]interface{}, error) {
+	if s.columns == nil {
+		return RowDataToDict(row, s.header, s.opts.ConvertType), nil
+	}
+	dict := make(map[string]interface{}, len(s.header))
+	for colIdx, key := range s.header {
+		col := s.columns[colIdx]
+		if colIdx >= len(row) {
+			dict[key] = nil
+			continue
+		}
+		val := strings.TrimSpace(row[colIdx])
+		switch {
+		case col != nil:
+			converted, err := col.Convert(val)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = converted
+		case s.opts.ConvertType:
+			dict[key] = convertActualType(val)
+		default:
+			dict[key] = val
+		}
+	}
+	return dict, nil
+}
@@ -0,0 +1,88 @@
+package csvutils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/winezer0/xutils/timeutils"
+)
+
+// TestInferCSVSchema 测试按列推断类型并在读取时统一转换
+func TestInferCSVSchema(t *testing.T) {
+	content := "id,score,active,created,note,code\n" +
+		"1,3,true,2026-03-12,hello,0012\n" +
+		"2,4.5,FALSE,2026-03-13,,12/03/2026\n" +
+		"3,,true,2026-03-14,world,13/03/2026\n"
+	filePath := makeTempCSV(t, "schema.csv", content)
+
+	schema, err := InferCSVSchema(filePath, 0)
+	if err != nil {
+		t.Fatalf("infer schema failed: %v", err)
+	}
+	expected := []ColumnSchema{
+		{Name: "id", Type: ColumnInt},
+		{Name: "score", Type: ColumnFloat, Nullable: true},
+		{Name: "active", Type: ColumnBool},
+		{Name: "created", Type: ColumnTime, Layout: timeutils.LayoutDate},
+		{Name: "note", Type: ColumnString, Nullable: true},
+		{Name: "code", Type: ColumnString},
+	}
+	if !reflect.DeepEqual(schema.Columns, expected) {
+		t.Fatalf("unexpected schema: %+v", schema.Columns)
+	}
+
+	// 只采样首行时 score 推断为整数
+	sampled, err := InferCSVSchema(filePath, 1)
+	if err != nil {
+		t.Fatalf("infer sampled schema failed: %v", err)
+	}
+	if col, _ := sampled.Column("score"); col.Type != ColumnInt {
+		t.Errorf("unexpected sampled score type: %v", col.Type)
+	}
+
+	opts := DefaultReadOptions()
+	opts.Schema = schema
+	var rows []map[string]interface{}
+	err = ReadBigCSVToDictsWithOptions(filePath, opts, func(m map[string]interface{}) error {
+		rows = append(rows, m)
+		return nil
+	})
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("read with schema failed: %v, %d rows", err, len(rows))
+	}
+	if rows[0]["score"] != float64(3) || rows[2]["score"] != nil {
+		t.Errorf("score not converted consistently: %v, %v", rows[0]["score"], rows[2]["score"])
+	}
+	if rows[1]["active"] != false || rows[0]["code"] != "0012" || rows[1]["note"] != nil {
+		t.Errorf("unexpected row values: %v, %v", rows[0], rows[1])
+	}
+	if created, ok := rows[0]["created"].(time.Time); !ok || !created.Equal(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created: %v", rows[0]["created"])
+	}
+
+	// 按列覆盖时间布局，严格模式下首行不符合布局的数据在迭代时报错
+	opts.ColumnTypes = map[string]ColumnSchema{"code": {Type: ColumnTime, Layout: "02/01/2006"}}
+	opts.Strict = true
+	iter, err := NewCSVIteratorWithOptions(filePath, opts)
+	if err != nil {
+		t.Fatalf("create iterator failed: %v", err)
+	}
+	defer iter.Close()
+	if row := iter.Next(); row != nil || iter.Error() == nil {
+		t.Errorf("expected convert error, got %v, %v", row, iter.Error())
+	}
+
+	opts.Strict = false
+	var codes []interface{}
+	err = ReadBigCSVToDictsWithOptions(filePath, opts, func(m map[string]interface{}) error {
+		codes = append(codes, m["code"])
+		return nil
+	})
+	if err != nil || len(codes) != 2 {
+		t.Fatalf("unexpected codes: %v, %v", codes, err)
+	}
+	if code, ok := codes[1].(time.Time); !ok || code.Day() != 13 {
+		t.Errorf("unexpected code: %v", codes[1])
+	}
+}
@@ -0,0 +1,12 @@
+package csvutils
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger ReadOptions.Logger 与上下文均未携带日志器时使用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置 CSV 读取跳过错误行、追加写入时表头不一致等提示的输出，
+// ReadOptions.Logger 或上下文中的日志器优先，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
@@ -0,0 +1,44 @@
+package csvutils
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordLogger 记录日志内容，用于验证库内部输出走注入的日志器
+type recordLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordLogger) add(level, template string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, level+" "+fmt.Sprintf(template, args...))
+}
+
+func (r *recordLogger) Debugf(template string, args ...interface{}) {
+	r.add("DEBUG", template, args...)
+}
+func (r *recordLogger) Infof(template string, args ...interface{}) { r.add("INFO", template, args...) }
+func (r *recordLogger) Warnf(template string, args ...interface{}) { r.add("WARN", template, args...) }
+func (r *recordLogger) Errorf(template string, args ...interface{}) {
+	r.add("ERROR", template, args...)
+}
+
+// TestSetLogger 测试表头不一致的提示输出到注入的日志器
+func TestSetLogger(t *testing.T) {
+	rec := &recordLogger{}
+	SetLogger(rec)
+	defer SetLogger(nil)
+
+	filePath := makeTempCSV(t, "logger.csv", "a,b\n1,2\n")
+	if !ShouldWriteHeader(filePath, []string{"a", "b", "c"}, false, ',') {
+		t.Fatal("expected header mismatch")
+	}
+	if len(rec.lines) != 1 || !strings.HasPrefix(rec.lines[0], "WARN file ") {
+		t.Fatalf("unexpected log lines: %v", rec.lines)
+	}
+}
@@ -1,18 +1,14 @@
 package csvutils
 
 import (
-	"bufio"
 	"context"
-	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"sync"
 )
 
-// ReadBigCSVToDictsWithCallback 兼容所有 Go 版本的回调版读取函数
-// 解决：BufferSize 未解析 + LazyQuotes/FieldsPerRecord 作用域问题
+// ReadBigCSVToDictsWithCallback 回调版读取函数，逐行转换为字典后交给 callback 处理，
+// 读取失败的行输出警告后跳过，callback 返回错误时终止读取
 func ReadBigCSVToDictsWithCallback(
 	filePath string,
 	delimiter rune,
@@ -20,93 +16,55 @@ func ReadBigCSVToDictsWithCallback(
 	convertType bool,
 	callback func(map[string]interface{}) error,
 ) error {
+	opts := bigReadOptions(delimiter, haveHeader)
+	opts.ConvertType = convertType
+	return ReadBigCSVToDictsWithOptions(filePath, opts, callback)
+}
+
+// ReadBigCSVToDictsWithOptions 按读取选项逐行读取大文件并交给 callback 处理
+func ReadBigCSVToDictsWithOptions(filePath string, opts ReadOptions, callback func(map[string]interface{}) error) error {
+	_, err := ReadBigCSVToDictsWithSummary(filePath, opts, callback)
+	return err
+}
+
+// ReadBigCSVToDictsWithSummary 按读取选项逐行读取大文件并交给 callback 处理，返回读取、跳过与失败的记录数。
+// 失败的记录在宽松模式下跳过，严格模式下返回 *RowError，均会交给 opts.OnRowError 处理
+func ReadBigCSVToDictsWithSummary(filePath string, opts ReadOptions, callback func(map[string]interface{}) error) (ReadSummary, error) {
+	var summary ReadSummary
 	// 边界校验
 	if callback == nil {
-		return errors.New("callback function cannot be nil")
+		return summary, errors.New("callback function cannot be nil")
 	}
 
-	// 1. 打开文件（只读模式）
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
+	src, err := openCSVSource(filePath, opts)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("file not found: %s", filePath)
-		}
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied to open file: %s", filePath)
-		}
-		return fmt.Errorf("open file failed: %w", err)
-	}
-	defer file.Close()
-
-	// 2. 手动创建带缓冲的 Reader（替代 csv.Reader.BufferSize，兼容所有版本）
-	// 1MB 缓冲区（适配大文件，和原 BufferSize=1024*1024 效果一致）
-	bufReader := bufio.NewReaderSize(file, 1024*1024)
-
-	// 3. 初始化 CSV Reader（核心：解决所有未解析引用）
-	reader := csv.NewReader(bufReader) // 包装缓冲 reader
-	if delimiter != 0 {
-		reader.Comma = delimiter // 设置自定义分隔符
+		return summary, fmt.Errorf("read header failed: %w", err)
 	}
-	// 关键配置（兼容所有 Go 版本）
-	reader.FieldsPerRecord = -1 // 允许字段数不一致（核心容错）
-	reader.LazyQuotes = true    // 宽松引号解析（容错非标准 CSV）
-	// 可选：额外容错配置（提升兼容性）
-	reader.TrimLeadingSpace = true // 自动修剪字段前后空格
+	defer src.Close()
 
-	// 4. 读取表头（逻辑不变）
-	var header []string
-	if haveHeader {
-		row, err := reader.Read()
-		if err != nil {
-			return fmt.Errorf("read header failed: %w", err)
-		}
-		header = RepairHeaders(row)
-	} else {
-		// 无表头时，先读第一行确定列数
-		firstRow, err := reader.Read()
-		if err != nil {
-			return fmt.Errorf("read first row failed: %w", err)
-		}
-		header = GenDefaultHeaders(len(firstRow))
-		// 处理第一行数据
-		dict := RowDataToDict(firstRow, header, convertType)
-		if err := callback(dict); err != nil {
-			return fmt.Errorf("callback failed at first row: %w", err)
-		}
-	}
-
-	// 5. 逐行读取+处理（核心逻辑不变）
-	lineNum := 1
+	// 逐行读取+处理
+	// 行号为记录起始的物理行号
 	for {
-		row, err := reader.Read()
+		dict, err := src.nextDict(&summary)
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				break // 正常结束
+				return summary, nil // 正常结束
 			}
-			// 单行错误：警告+跳过
-			fmt.Printf("warning: skip row %d, read error: %v\n", lineNum, err)
-			lineNum++
-			continue
+			return summary, err
 		}
-
-		lineNum++
-		// 行数据转字典
-		dict := RowDataToDict(row, header, convertType)
 		// 调用回调处理
 		if err := callback(dict); err != nil {
-			return fmt.Errorf("callback failed at row %d: %w", lineNum, err)
+			return summary, fmt.Errorf("callback failed at row %d: %w", src.Line(), err)
 		}
 	}
-
-	return nil
 }
 
-// ReadBigCSVWithConsumers 并发处理超大CSV（生产者+消费者模型）
+// ReadBigCSVWithConsumers 并发处理超大CSV（生产者+消费者模型），基于 RunCSVPipeline 实现
 // 参数：
 //
 //	filePath      - CSV文件路径
 //	delimiter     - 分隔符
-//	skipHeader    - 是否跳过表头
+//	haveHeader    - 首行是否为表头
 //	convertType   - 是否自动转换类型
 //	consumerCount - 消费者数量（建议=CPU核心数*2）
 //	chanBuffer    - 通道缓冲区大小（建议=consumerCount*10）
@@ -114,7 +72,9 @@ func ReadBigCSVToDictsWithCallback(
 //
 // 返回值：
 //
-//	err - 致命错误（文件读取失败/消费者全部崩溃）
+//	err - 文件读取失败、ctx 被取消，或合并后的全部行错误（包含行号），单行失败不影响其他行的处理
+//
+// ctx 中通过 liblog.WithContext 或 logging.WithContext 保存的日志器优先于包级日志器，用于区分不同任务的日志
 func ReadBigCSVWithConsumers(
 	ctx context.Context,
 	filePath string,
@@ -129,90 +89,22 @@ func ReadBigCSVWithConsumers(
 	if consumerCount <= 0 {
 		return errors.New("consumerCount must be > 0")
 	}
-	if chanBuffer <= 0 {
-		chanBuffer = consumerCount * 10 // 默认缓冲区大小
-	}
 	if consumerFunc == nil {
 		return errors.New("consumerFunc cannot be nil")
 	}
 
-	// 1. 创建带缓冲的通道（核心：控速+解耦）
-	dataChan := make(chan map[string]interface{}, chanBuffer)
-	// 错误通道：收集消费者/生产者的错误
-	errChan := make(chan error, consumerCount+1)
-	// 退出信号：通知消费者停止
-	doneChan := make(chan struct{})
-
-	// 2. 启动消费者池
-	var wg sync.WaitGroup
-	for i := 0; i < consumerCount; i++ {
-		wg.Add(1)
-		go func(consumerID int) {
-			defer wg.Done()
-			for {
-				select {
-				case <-ctx.Done(): // 外部中断（如超时/手动停止）
-					fmt.Printf("consumer %d: context canceled, exit\n", consumerID)
-					return
-				case <-doneChan: // 生产者完成，无更多数据
-					fmt.Printf("consumer %d: no more data, exit\n", consumerID)
-					return
-				case data, ok := <-dataChan: // 从通道取数据
-					if !ok {
-						return
-					}
-					// 执行消费逻辑
-					if err := consumerFunc(data); err != nil {
-						// 记录错误，但不终止其他消费者（容错）
-						errChan <- fmt.Errorf("consumer %d process data failed: %w", consumerID, err)
-						// 可选：若为致命错误，触发全局中断
-						// ctx.cancel()
-					}
-				}
-			}
-		}(i)
+	opts := PipelineOptions{
+		Read:       bigReadOptions(delimiter, haveHeader),
+		Workers:    consumerCount,
+		BufferSize: chanBuffer,
 	}
+	opts.Read.ConvertType = convertType
 
-	// 3. 启动生产者（逐行读取CSV并发送到通道）
-	go func() {
-		defer close(dataChan) // 生产者完成，关闭数据通道
-		// 调用回调版读取函数，将数据发送到通道
-		err := ReadBigCSVToDictsWithCallback(
-			filePath,
-			delimiter,
-			haveHeader,
-			convertType,
-			func(dict map[string]interface{}) error {
-				select {
-				case <-ctx.Done(): // 外部中断，停止生产
-					return ctx.Err()
-				case dataChan <- dict: // 发送数据到通道（缓冲区满时阻塞，自动控速）
-					return nil
-				}
-			},
-		)
-		if err != nil {
-			errChan <- fmt.Errorf("producer read csv failed: %w", err)
-		}
-	}()
-
-	// 4. 等待生产者完成 + 消费者处理完毕
-	go func() {
-		// 等待所有数据发送完成（通道关闭）
-		// 通知消费者无更多数据
-		close(doneChan)
-		// 等待所有消费者完成
-		wg.Wait()
-		// 关闭错误通道
-		close(errChan)
-	}()
-
-	// 5. 收集错误（返回第一个致命错误）
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
+	result, err := RunCSVPipeline(ctx, filePath, opts, func(_ context.Context, row PipelineRow) (struct{}, error) {
+		return struct{}{}, consumerFunc(row.Data)
+	}, nil)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return result.Err()
 }
@@ -1,65 +1,42 @@
 package csvutils
 
 import (
-	"bufio"
-	"encoding/csv"
 	"errors"
 	"fmt"
-	"io"
-	"os"
 )
 
-// CSVIterator CSV 迭代器（用于逐行读取大文件）
-// 修复点：
-// 1. 新增 file 字段保存文件句柄（替代访问 csv.Reader 私有 Input 字段）
-// 2. 添加缓冲读取（适配大文件）
-// 3. 补充所有缺失的辅助函数
-// 4. 增强容错配置（LazyQuotes、TrimLeadingSpace）
+// CSVIterator CSV 迭代器（用于逐行读取大文件），解析规则由 ReadOptions 决定
 type CSVIterator struct {
-	reader      *csv.Reader
-	file        *os.File // 新增：保存文件句柄，用于关闭
-	header      []string
-	convertType bool
-	lineNum     int
-	err         error
+	src     *csvSource
+	header  []string
+	summary ReadSummary
+	err     error
+	store   CheckpointStore // 检查点存储，通过 Resume 创建时设置
+	rows    int             // 已交付的数据记录数，恢复时从检查点的记录数开始
 }
 
 // NewCSVIterator 创建大文件 CSV 迭代器（修复版）
 func NewCSVIterator(filePath string, delimiter rune, convertType bool) (*CSVIterator, error) {
-	// 1. 打开文件（保存句柄，用于后续关闭）
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
-	if err != nil {
-		return nil, fmt.Errorf("open file failed: %w", err)
-	}
-
-	// 2. 手动创建带缓冲的 reader（适配大文件，替代 csv.Reader.BufferSize）
-	bufReader := bufio.NewReaderSize(file, 1024*1024) // 1MB 缓冲区
-
-	// 3. 初始化 CSV Reader（增强容错）
-	reader := csv.NewReader(bufReader)
-	if delimiter != 0 {
-		reader.Comma = delimiter
-	}
-	// 核心容错配置
-	reader.FieldsPerRecord = -1    // 允许字段数不一致
-	reader.LazyQuotes = true       // 宽松引号解析
-	reader.TrimLeadingSpace = true // 自动修剪字段前后空格
-
-	iter := &CSVIterator{
-		reader:      reader,
-		file:        file, // 保存文件句柄
-		convertType: convertType,
-		lineNum:     1,
-	}
+	opts := bigReadOptions(delimiter, true)
+	opts.ConvertType = convertType
+	return NewCSVIteratorWithOptions(filePath, opts)
+}
 
-	// 4. 读取表头
-	row, err := reader.Read()
+// NewCSVIteratorWithOptions 按读取选项创建大文件 CSV 迭代器
+func NewCSVIteratorWithOptions(filePath string, opts ReadOptions) (*CSVIterator, error) {
+	src, err := openCSVSource(filePath, opts)
 	if err != nil {
-		file.Close() // 读取失败时关闭文件
 		return nil, fmt.Errorf("read header failed: %w", err)
 	}
-	iter.header = RepairHeaders(row)
-	return iter, nil
+	return &CSVIterator{
+		src:    src,
+		header: src.Header(),
+	}, nil
+}
+
+// Header 返回迭代器使用的列名
+func (iter *CSVIterator) Header() []string {
+	return iter.header
 }
 
 // Next 读取下一行（返回nil表示读取完毕）
@@ -68,25 +45,24 @@ func (iter *CSVIterator) Next() map[string]interface{} {
 		return nil
 	}
 
-	row, err := iter.reader.Read()
+	// 失败的记录按 Strict 与 OnRowError 处理，宽松模式下跳过后继续迭代
+	dict, err := iter.src.nextDict(&iter.summary)
 	if err != nil {
-		if errors.Is(err, io.EOF) {
-			iter.err = err
-			return nil
-		}
-		// 单行读取失败：记录错误，返回nil（不中断迭代）
-		iter.err = fmt.Errorf("read row %d failed: %w", iter.lineNum, err)
+		iter.err = err
 		return nil
 	}
+	iter.rows++
+	return dict
+}
 
-	// 首次读取确定默认表头（无表头场景）
-	if len(iter.header) == 0 {
-		iter.header = GenDefaultHeaders(len(row))
-	}
+// Line 返回最近读取的记录的起始物理行号
+func (iter *CSVIterator) Line() int {
+	return iter.src.Line()
+}
 
-	iter.lineNum++
-	// 行数据转字典
-	return RowDataToDict(row, iter.header, iter.convertType)
+// Summary 返回截至目前的读取统计
+func (iter *CSVIterator) Summary() ReadSummary {
+	return iter.summary
 }
 
 // Error 返回迭代过程中的错误
@@ -94,10 +70,20 @@ func (iter *CSVIterator) Error() error {
 	return iter.err
 }
 
-// Close 关闭迭代器（修复版：直接关闭保存的 file 句柄）
+// Close 关闭迭代器
 func (iter *CSVIterator) Close() error {
-	if iter.file != nil {
-		return iter.file.Close()
+	return iter.src.Close()
+}
+
+// Checkpoint 返回最近一次 Next 返回的记录之后位置的检查点
+func (iter *CSVIterator) Checkpoint() Checkpoint {
+	return iter.src.checkpoint(iter.rows)
+}
+
+// Commit 将当前位置保存到检查点存储，之后通过 Resume 可从下一条记录继续读取，仅 Resume 创建的迭代器可用
+func (iter *CSVIterator) Commit() error {
+	if iter.store == nil {
+		return errors.New("iterator has no checkpoint store, create it with Resume")
 	}
-	return nil
+	return iter.store.Save(iter.Checkpoint())
 }
@@ -0,0 +1,363 @@
+package csvutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/winezer0/xutils/liblog"
+	"github.com/winezer0/xutils/utils"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// HeaderMode 表头处理方式
+type HeaderMode int
+
+const (
+	HeaderFirstRow    HeaderMode = iota // 首行作为表头，表头会经过 RepairHeaders 清洗
+	HeaderNone                          // 没有表头，所有行都是数据，列名为 col0、col1...
+	HeaderIgnore                        // 丢弃首行表头，列名为 col0、col1...
+	HeaderFirstRowRaw                   // 首行作为表头，原样返回不经过 RepairHeaders 清洗
+)
+
+// ErrFieldTooLarge 字段长度超过 ReadOptions.MaxFieldSize
+var ErrFieldTooLarge = errors.New("csv field too large")
+
+// ReadOptions CSV 读取选项，所有读取函数共用同一套解析规则
+type ReadOptions struct {
+	Delimiter    rune          // 分隔符，0 表示根据首个非空行自动检测
+	Comment      rune          // 注释字符，以该字符开头的行被忽略，0 表示不启用
+	SkipRows     int           // 解析前跳过的文件开头物理行数，用于跳过导出工具写入的说明行
+	HeaderMode   HeaderMode    // 表头处理方式
+	TrimSpace    bool          // 去除字段首尾空白
+	TrimLeading  bool          // 只去除字段开头空白，TrimSpace 为 true 时无需设置
+	LazyQuotes   bool          // 宽松引号解析，容错非标准 CSV
+	FixedFields  bool          // 要求每条记录的字段数与首条记录相同，不同时按格式错误处理，默认允许字段数不一致
+	Encoding     string        // 文件编码，如 utf-8、gbk、utf-16le，空串表示自动检测，无法识别的编码按 utf-8 读取
+	MaxFieldSize int           // 单个字段最大字节数，0 表示不限制；字段由 csv.Reader 完整解析后才检查，只用于拒绝超长记录，不能限制内存占用
+	BufferSize   int           // 读取缓冲区大小，0 表示1MB
+	ConvertType  bool          // 转换为字典时是否自动推断数据类型，逐个字段推断，同一列可能得到不同类型
+	Logger       liblog.Logger // 输出跳过错误行等提示，为空时使用包级日志器
+
+	Strict     bool            // 严格模式，记录格式错误、字段超长或类型转换失败时终止读取，默认跳过失败的记录继续读取
+	OnRowError RowErrorHandler // 失败记录的处理函数，可用于输出拒绝记录文件，为空时宽松模式下输出警告日志
+
+	Schema      *CSVSchema              // 列类型定义，通常由 InferCSVSchema 生成，设置后转换为字典时每列统一按定义转换，优先于 ConvertType
+	ColumnTypes map[string]ColumnSchema // 按列名覆盖 Schema 中的定义，如指定日期列的布局，也可不设置 Schema 单独使用
+}
+
+// DefaultReadOptions 返回默认读取选项：自动检测分隔符、首行为表头、去除空白、宽松引号
+func DefaultReadOptions() ReadOptions {
+	return ReadOptions{
+		HeaderMode: HeaderFirstRow,
+		TrimSpace:  true,
+		LazyQuotes: true,
+		BufferSize: 1024 * 1024,
+	}
+}
+
+// plainReadOptions 旧版 ReadCSV2Rows、GetCSVHeaders、CountCSVLines 使用的选项：
+// 分隔符为 0 时使用逗号，不去除空白，不启用宽松引号
+func plainReadOptions(delimiter rune, mode HeaderMode) ReadOptions {
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	return ReadOptions{Delimiter: delimiter, HeaderMode: mode}
+}
+
+// bigReadOptions 旧版大文件读取函数使用的选项：分隔符为 0 时使用逗号，
+// 宽松引号，去除字段首尾空白，haveHeader 对应 HeaderFirstRow 或 HeaderNone
+func bigReadOptions(delimiter rune, haveHeader bool) ReadOptions {
+	opts := DefaultReadOptions()
+	opts.Delimiter = delimiter
+	if delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if !haveHeader {
+		opts.HeaderMode = HeaderNone
+	}
+	return opts
+}
+
+// normalizeReadOptions 校验并补全读取选项
+func normalizeReadOptions(opts ReadOptions) (ReadOptions, error) {
+	if opts.SkipRows < 0 {
+		return opts, errors.New("skip rows cannot be negative")
+	}
+	if opts.MaxFieldSize < 0 {
+		return opts, errors.New("max field size cannot be negative")
+	}
+	if opts.HeaderMode < HeaderFirstRow || opts.HeaderMode > HeaderFirstRowRaw {
+		return opts, fmt.Errorf("invalid header mode: %d", opts.HeaderMode)
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024 * 1024
+	}
+	opts.Logger = liblog.Or(opts.Logger, pkgLogger.Get())
+	return opts, nil
+}
+
+// csvSource 按读取选项打开的 CSV 数据源，负责解码、跳行、分隔符检测与表头处理
+type csvSource struct {
+	file       *os.File
+	reader     *csv.Reader
+	opts       ReadOptions
+	header     []string
+	pending    []string        // HeaderNone 时为确定列数预读的首行数据
+	pos        recordPos       // 最近读取的记录的位置
+	pendingPos recordPos       // 预读首行的位置
+	columns    []*ColumnSchema // 按列顺序排列的列类型定义，未设置类型时为空
+	base       int64           // csv.Reader 开始读取时在解码后数据流中的字节偏移
+	lineBase   int             // csv.Reader 开始读取前已经过的物理行数
+}
+
+// recordPos 记录在文件中的位置
+type recordPos struct {
+	line    int   // 起始物理行号，读取出错时为出错记录的起始行号
+	endLine int   // 结束物理行号，字段中包含换行时大于 line
+	offset  int64 // 记录结束（含换行符）处在解码后数据流中的字节偏移，即下一条记录的起始偏移
+}
+
+// openCSVSource 打开 CSV 文件并读取表头，文件为空时返回 io.EOF
+func openCSVSource(filePath string, opts ReadOptions) (*csvSource, error) {
+	opts, err := normalizeReadOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	file, err := openCSVFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Encoding = resolveEncoding(filePath, file, opts.Encoding)
+	src, err := newCSVSource(file, opts)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	src.file = file
+	return src, nil
+}
+
+// openCSVFile 以只读方式打开 CSV 文件
+func openCSVFile(filePath string) (*os.File, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", filePath)
+		}
+		if os.IsPermission(err) {
+			return nil, fmt.Errorf("permission denied to open file: %s", filePath)
+		}
+		return nil, fmt.Errorf("open file failed: %w", err)
+	}
+	return file, nil
+}
+
+// newCSVSource 基于已打开的数据流创建数据源并读取表头
+func newCSVSource(r io.Reader, opts ReadOptions) (*csvSource, error) {
+	br := bufio.NewReaderSize(decodeReader(r, opts.Encoding), opts.BufferSize)
+	base := int64(skipUTF8BOM(br))
+	skipped, lines, err := skipLines(br, opts.SkipRows)
+	if err != nil {
+		return nil, fmt.Errorf("skip rows failed: %w", err)
+	}
+	base += skipped
+	if opts.Delimiter == 0 {
+		opts.Delimiter = peekDelimiter(br, opts.Comment)
+	}
+
+	src := &csvSource{reader: newCSVReader(br, opts), opts: opts, base: base, lineBase: lines}
+	if err := src.readHeader(); err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// newCSVReader 按读取选项创建 csv.Reader
+func newCSVReader(r io.Reader, opts ReadOptions) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Delimiter
+	reader.Comment = opts.Comment
+	reader.FieldsPerRecord = -1 // 允许字段数不一致
+	if opts.FixedFields {
+		reader.FieldsPerRecord = 0 // 以首条记录的字段数为准
+	}
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.TrimLeadingSpace = opts.TrimSpace || opts.TrimLeading
+	return reader
+}
+
+// readHeader 按表头模式确定列名
+func (s *csvSource) readHeader() error {
+	row, err := s.readRecord()
+	if err != nil {
+		return err
+	}
+	switch s.opts.HeaderMode {
+	case HeaderNone:
+		s.header = GenDefaultHeaders(len(row))
+		s.pending = row
+		s.pendingPos = s.pos
+	case HeaderIgnore:
+		s.header = GenDefaultHeaders(len(row))
+	case HeaderFirstRowRaw:
+		s.header = row
+	default:
+		s.header = RepairHeaders(row)
+	}
+	s.columns = resolveColumns(s.header, s.opts)
+	return nil
+}
+
+// Header 返回列名
+func (s *csvSource) Header() []string {
+	return s.header
+}
+
+// Read 读取下一条数据记录，读取完毕时返回 io.EOF
+func (s *csvSource) Read() ([]string, error) {
+	if s.pending != nil {
+		row := s.pending
+		s.pending = nil
+		s.pos = s.pendingPos
+		return row, nil
+	}
+	return s.readRecord()
+}
+
+// Line 返回最近读取的记录或出错位置的起始物理行号
+func (s *csvSource) Line() int {
+	return s.pos.line
+}
+
+// readRecord 读取一条记录并应用字段长度限制与空白处理，字段超长时同时返回原始记录
+func (s *csvSource) readRecord() ([]string, error) {
+	row, err := s.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			s.pos = recordPos{line: s.lineBase + parseErr.StartLine, endLine: s.lineBase + parseErr.Line, offset: s.base + s.reader.InputOffset()}
+		}
+		return nil, err
+	}
+	line, _ := s.reader.FieldPos(0)
+	s.pos = recordPos{line: s.lineBase + line, endLine: s.lineBase + line, offset: s.base + s.reader.InputOffset()}
+	for i, field := range row {
+		s.pos.endLine += strings.Count(field, "\n")
+		if s.opts.MaxFieldSize > 0 && len(field) > s.opts.MaxFieldSize {
+			line, col := s.reader.FieldPos(i)
+			return row, fmt.Errorf("line %d column %d: %w (%d > %d)", s.lineBase+line, col, ErrFieldTooLarge, len(field), s.opts.MaxFieldSize)
+		}
+	}
+	for i, field := range row {
+		if s.opts.TrimSpace {
+			row[i] = strings.TrimSpace(field)
+		}
+	}
+	return row, nil
+}
+
+// Close 关闭底层文件
+func (s *csvSource) Close() error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// decodeReader 按编码名称将数据流解码为 UTF-8，无法识别的编码按 UTF-8 读取
+func decodeReader(r io.Reader, encoding string) io.Reader {
+	if isUTF8Encoding(encoding) {
+		return r
+	}
+	return transform.NewReader(r, utils.NormalizedEncode(strings.TrimSpace(encoding)).NewDecoder())
+}
+
+// isUTF8Encoding 判断编码名称是否按 UTF-8 读取，此时解码后的偏移即文件偏移
+func isUTF8Encoding(encoding string) bool {
+	return utils.NormalizedEncode(strings.TrimSpace(encoding)) == unicode.UTF8
+}
+
+// resolveEncoding 确定文件编码，未指定时合法的 UTF-8 内容直接按 UTF-8 读取，
+// 带 UTF-16/UTF-32 BOM 或无法按 UTF-8 解析的内容交给 utils.DetectFileEncode 检测
+func resolveEncoding(filePath string, file *os.File, encoding string) string {
+	if encoding != "" {
+		return encoding
+	}
+	head := make([]byte, 4096)
+	n, _ := io.ReadFull(file, head)
+	head = head[:n]
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "utf-8"
+	}
+	if isUTF8Text(head) {
+		return "utf-8"
+	}
+	return utils.DetectFileEncode(filePath, "")
+}
+
+// isUTF8Text 判断采样内容是否为 UTF-8 文本，允许末尾被截断的多字节字符，包含 NUL 字节时视为 UTF-16/UTF-32
+func isUTF8Text(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	for cut := 0; cut < utf8.UTFMax && cut <= len(head); cut++ {
+		if utf8.Valid(head[:len(head)-cut]) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipUTF8BOM 跳过开头的 UTF-8 BOM，返回跳过的字节数
+func skipUTF8BOM(br *bufio.Reader) int {
+	if head, err := br.Peek(3); err == nil && bytes.Equal(head, []byte{0xEF, 0xBB, 0xBF}) {
+		n, _ := br.Discard(3)
+		return n
+	}
+	return 0
+}
+
+// skipLines 跳过 n 个物理行，返回跳过的字节数与行数
+func skipLines(br *bufio.Reader, n int) (int64, int, error) {
+	var skipped int64
+	for i := 0; i < n; i++ {
+		line, err := br.ReadBytes('\n')
+		skipped += int64(len(line))
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return skipped, i, nil
+			}
+			return skipped, i, err
+		}
+	}
+	return skipped, n, nil
+}
+
+// peekDelimiter 在不消耗数据的前提下根据缓冲区中首个非空且非注释的行检测分隔符
+func peekDelimiter(br *bufio.Reader, comment rune) rune {
+	head, _ := br.Peek(br.Size())
+	if comment != 0 {
+		prefix := []byte(string(comment))
+		for bytes.HasPrefix(head, prefix) {
+			idx := bytes.IndexByte(head, '\n')
+			if idx < 0 {
+				return ','
+			}
+			head = head[idx+1:]
+		}
+	}
+	delimiter, err := detectCSVDelimiter(bytes.NewReader(head))
+	if err != nil || delimiter == 0 {
+		return ','
+	}
+	return delimiter
+}
@@ -0,0 +1,133 @@
+package csvutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// TestReadOptions 测试跳行、注释、自动分隔符与空白处理在各读取函数中保持一致
+func TestReadOptions(t *testing.T) {
+	content := "\ufeffexported by tool\n# comment\nname; age\n张三; 25\n# skipped\n\"李\n四\";30\n"
+	filePath := makeTempCSV(t, "options.csv", content)
+
+	opts := DefaultReadOptions()
+	opts.SkipRows = 1
+	opts.Comment = '#'
+
+	header, rows, err := ReadCSV2RowsWithOptions(filePath, opts)
+	if err != nil {
+		t.Fatalf("read rows failed: %v", err)
+	}
+	if !reflect.DeepEqual(header, []string{"name", "age"}) {
+		t.Errorf("unexpected header: %v", header)
+	}
+	expected := [][]string{{"张三", "25"}, {"李\n四", "30"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("unexpected rows: %q", rows)
+	}
+
+	headers, err := GetCSVHeadersWithOptions(filePath, opts)
+	if err != nil || !reflect.DeepEqual(headers, header) {
+		t.Errorf("unexpected headers: %v, %v", headers, err)
+	}
+	count, err := CountCSVLinesWithOptions(filePath, opts)
+	if err != nil || count != 2 {
+		t.Errorf("unexpected count: %d, %v", count, err)
+	}
+
+	iter, err := NewCSVIteratorWithOptions(filePath, opts)
+	if err != nil {
+		t.Fatalf("create iterator failed: %v", err)
+	}
+	defer iter.Close()
+	var names []interface{}
+	for row := iter.Next(); row != nil; row = iter.Next() {
+		names = append(names, row["name"])
+	}
+	if !reflect.DeepEqual(names, []interface{}{"张三", "李\n四"}) {
+		t.Errorf("unexpected iterator rows: %v", names)
+	}
+
+	opts.ConvertType = true
+	var ages []interface{}
+	err = ReadBigCSVToDictsWithOptions(filePath, opts, func(m map[string]interface{}) error {
+		ages = append(ages, m["age"])
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(ages, []interface{}{int64(25), int64(30)}) {
+		t.Errorf("unexpected callback rows: %v, %v", ages, err)
+	}
+}
+
+// TestReadOptionsHeaderMode 测试表头模式
+func TestReadOptionsHeaderMode(t *testing.T) {
+	filePath := makeTempCSV(t, "header_mode.csv", "a,b\n1,2\n")
+
+	opts := DefaultReadOptions()
+	opts.HeaderMode = HeaderNone
+	header, rows, err := ReadCSV2RowsWithOptions(filePath, opts)
+	if err != nil || !reflect.DeepEqual(header, []string{"col0", "col1"}) || len(rows) != 2 {
+		t.Errorf("unexpected HeaderNone result: %v %v %v", header, rows, err)
+	}
+
+	opts.HeaderMode = HeaderIgnore
+	header, rows, err = ReadCSV2RowsWithOptions(filePath, opts)
+	if err != nil || !reflect.DeepEqual(header, []string{"col0", "col1"}) || !reflect.DeepEqual(rows, [][]string{{"1", "2"}}) {
+		t.Errorf("unexpected HeaderIgnore result: %v %v %v", header, rows, err)
+	}
+}
+
+// TestReadOptionsLimits 测试字段长度限制与编码
+func TestReadOptionsLimits(t *testing.T) {
+	filePath := makeTempCSV(t, "limit.csv", "a,b\n1,0123456789\n")
+	opts := DefaultReadOptions()
+	opts.MaxFieldSize = 5
+	if _, _, err := ReadCSV2RowsWithOptions(filePath, opts); !errors.Is(err, ErrFieldTooLarge) {
+		t.Errorf("expected ErrFieldTooLarge, got %v", err)
+	}
+
+	gbk, err := simplifiedchinese.GBK.NewEncoder().String("姓名,城市\n张三,北京\n")
+	if err != nil {
+		t.Fatalf("encode gbk failed: %v", err)
+	}
+	gbkPath := makeTempCSV(t, "gbk.csv", gbk)
+	opts = DefaultReadOptions()
+	opts.Encoding = "gbk"
+	header, rows, err := ReadCSV2RowsWithOptions(gbkPath, opts)
+	if err != nil || !reflect.DeepEqual(header, []string{"姓名", "城市"}) || !reflect.DeepEqual(rows, [][]string{{"张三", "北京"}}) {
+		t.Errorf("unexpected gbk result: %v %v %v", header, rows, err)
+	}
+}
+
+// TestLegacyReadSemantics 测试旧版读取函数保持原有解析规则，新的默认选项只在 WithOptions 函数中生效
+func TestLegacyReadSemantics(t *testing.T) {
+	filePath := makeTempCSV(t, "legacy.csv", "\" a \", b ,a\n x , y ,z\n")
+	header, rows, err := ReadCSV2Rows(filePath, 0, true)
+	if err != nil {
+		t.Fatalf("read rows failed: %v", err)
+	}
+	if !reflect.DeepEqual(header, []string{" a ", " b ", "a"}) || !reflect.DeepEqual(rows, [][]string{{" x ", " y ", "z"}}) {
+		t.Errorf("unexpected legacy rows: %q %q", header, rows)
+	}
+
+	var dicts []map[string]interface{}
+	err = ReadBigCSVToDictsWithCallback(filePath, 0, true, false, func(m map[string]interface{}) error {
+		dicts = append(dicts, m)
+		return nil
+	})
+	if err != nil || len(dicts) != 1 || dicts[0]["b"] != "y" {
+		t.Errorf("unexpected legacy dicts: %v, %v", dicts, err)
+	}
+
+	quotePath := makeTempCSV(t, "legacy_quote.csv", "a,b\n1,x\"y\n")
+	if _, _, err := ReadCSV2Rows(quotePath, 0, true); err == nil {
+		t.Error("expected bare quote error without lazy quotes")
+	}
+	semicolonPath := makeTempCSV(t, "legacy_semicolon.csv", "a;b\n1;2\n")
+	if header, _, err := ReadCSV2Rows(semicolonPath, 0, true); err != nil || !reflect.DeepEqual(header, []string{"a;b"}) {
+		t.Errorf("expected comma delimiter by default: %v, %v", header, err)
+	}
+}
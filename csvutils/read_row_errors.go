@@ -0,0 +1,99 @@
+package csvutils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RowErrorHandler 行错误处理函数，接收记录起始物理行号、原始记录与错误，
+// CSV 格式错误时无法得到原始记录，record 为 nil。返回错误时终止读取
+type RowErrorHandler func(line int, record []string, err error) error
+
+// RowError 严格模式下导致读取终止的行错误
+type RowError struct {
+	Line   int      // 记录起始物理行号
+	Record []string // 原始记录，CSV 格式错误时为 nil
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadSummary 读取结果统计
+type ReadSummary struct {
+	Read    int // 读取的数据记录数，包含失败的记录
+	Skipped int // 宽松模式下因失败被跳过的记录数
+	Failed  int // 格式错误、字段超长或类型转换失败的记录数
+}
+
+// isRowError 判断错误是否只影响当前记录，可跳过后继续读取
+func isRowError(err error) bool {
+	var parseErr *csv.ParseError
+	return errors.As(err, &parseErr) || errors.Is(err, ErrFieldTooLarge)
+}
+
+// nextRecord 读取下一条可用记录，失败的记录按 Strict 与 OnRowError 处理，
+// 读取完毕时返回 io.EOF，其他错误均终止读取
+func (s *csvSource) nextRecord(summary *ReadSummary) ([]string, error) {
+	for {
+		row, err := s.Read()
+		if err == nil {
+			summary.Read++
+			return row, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if !isRowError(err) {
+			return nil, fmt.Errorf("read row %d failed: %w", s.Line(), err)
+		}
+		summary.Read++
+		if err := s.rowFailed(summary, row, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextDict 读取下一条可用记录并转换为字典，类型转换失败的记录同样按 Strict 与 OnRowError 处理
+func (s *csvSource) nextDict(summary *ReadSummary) (map[string]interface{}, error) {
+	for {
+		row, err := s.nextRecord(summary)
+		if err != nil {
+			return nil, err
+		}
+		dict, err := s.rowToDict(row)
+		if err != nil {
+			if err := s.rowFailed(summary, row, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return dict, nil
+	}
+}
+
+// rowFailed 记录失败的行并交给 OnRowError 处理，未设置处理函数时在宽松模式下输出警告，
+// 严格模式下返回 *RowError 终止读取
+func (s *csvSource) rowFailed(summary *ReadSummary, record []string, err error) error {
+	summary.Failed++
+	line := s.Line()
+	if s.opts.OnRowError != nil {
+		if hErr := s.opts.OnRowError(line, record, err); hErr != nil {
+			return fmt.Errorf("row %d error handler failed: %w", line, hErr)
+		}
+	} else if !s.opts.Strict {
+		s.opts.Logger.Warnf("skip row %d: %v", line, err)
+	}
+	if s.opts.Strict {
+		return &RowError{Line: line, Record: record, Err: err}
+	}
+	summary.Skipped++
+	return nil
+}
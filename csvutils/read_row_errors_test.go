@@ -0,0 +1,91 @@
+package csvutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestRowErrorHandling 测试宽松与严格模式下失败记录的处理、物理行号与读取统计
+func TestRowErrorHandling(t *testing.T) {
+	content := "id,name\n1,\"multi\nline\"\n2,toolongvalue\nx,bob\n4,\"bad\"quote\n5,eve\n"
+	filePath := makeTempCSV(t, "rejects.csv", content)
+
+	type reject struct {
+		line   int
+		record []string
+	}
+	var rejects []reject
+	opts := DefaultReadOptions()
+	opts.LazyQuotes = false
+	opts.MaxFieldSize = 10
+	opts.ColumnTypes = map[string]ColumnSchema{"id": {Type: ColumnInt}}
+	opts.OnRowError = func(line int, record []string, err error) error {
+		rejects = append(rejects, reject{line: line, record: record})
+		return nil
+	}
+
+	var ids []interface{}
+	summary, err := ReadBigCSVToDictsWithSummary(filePath, opts, func(m map[string]interface{}) error {
+		ids = append(ids, m["id"])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("lenient read failed: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []interface{}{int64(1), int64(5)}) {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+	if summary != (ReadSummary{Read: 5, Skipped: 3, Failed: 3}) {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	expected := []reject{
+		{line: 4, record: []string{"2", "toolongvalue"}},
+		{line: 5, record: []string{"x", "bob"}},
+		{line: 6, record: nil},
+	}
+	if !reflect.DeepEqual(rejects, expected) {
+		t.Errorf("unexpected rejects: %+v", rejects)
+	}
+
+	// 严格模式在第一条失败记录处终止
+	opts.Strict = true
+	opts.OnRowError = nil
+	iter, err := NewCSVIteratorWithOptions(filePath, opts)
+	if err != nil {
+		t.Fatalf("create iterator failed: %v", err)
+	}
+	defer iter.Close()
+	count := 0
+	for row := iter.Next(); row != nil; row = iter.Next() {
+		count++
+	}
+	var rowErr *RowError
+	if count != 1 || !errors.As(iter.Error(), &rowErr) || rowErr.Line != 4 || !errors.Is(rowErr, ErrFieldTooLarge) {
+		t.Errorf("unexpected strict result: %d rows, %v", count, iter.Error())
+	}
+	if s := iter.Summary(); s.Failed != 1 || s.Skipped != 0 {
+		t.Errorf("unexpected strict summary: %+v", s)
+	}
+}
+
+// TestRowErrorLineWithSkipRows 测试跳过开头说明行后行号仍为文件中的物理行号
+func TestRowErrorLineWithSkipRows(t *testing.T) {
+	content := "# exported by scanner\n# 2 targets\nid,name\n1,alice\nx,bob\n"
+	filePath := makeTempCSV(t, "skip_rows.csv", content)
+
+	var lines []int
+	opts := DefaultReadOptions()
+	opts.SkipRows = 2
+	opts.ColumnTypes = map[string]ColumnSchema{"id": {Type: ColumnInt}}
+	opts.OnRowError = func(line int, record []string, err error) error {
+		lines = append(lines, line)
+		return nil
+	}
+	if _, err := ReadBigCSVToDictsWithSummary(filePath, opts, func(map[string]interface{}) error { return nil }); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !reflect.DeepEqual(lines, []int{5}) {
+		t.Errorf("unexpected reject lines: %v", lines)
+	}
+}
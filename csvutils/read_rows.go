@@ -19,50 +19,46 @@ import (
 //   - delimiter: 分隔符（0 表示默认逗号）。
 //   - haveHeader: 是否包含表头。
 //
+// 表头原样返回，字段不去除空白，不启用宽松引号；需要其他解析规则时使用 ReadCSV2RowsWithOptions。
+//
 // 返回值：
 //   - header: 表头切片（若无表头则为 nil）。
 //   - rows: 数据行切片。
 //   - err: 错误信息。
 func ReadCSV2Rows(filePath string, delimiter rune, haveHeader bool) (header []string, rows [][]string, err error) {
-	// 1. 打开文件
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("open file failed: %w", err)
-	}
-	defer file.Close()
+	return ReadCSV2RowsWithOptions(filePath, rowsReadOptions(delimiter, haveHeader))
+}
 
-	// 2. 初始化 Reader
-	reader := csv.NewReader(file)
-	if delimiter != 0 {
-		reader.Comma = delimiter
+// rowsReadOptions ReadCSV2Rows 使用的选项，haveHeader 对应 HeaderFirstRowRaw 或 HeaderNone
+func rowsReadOptions(delimiter rune, haveHeader bool) ReadOptions {
+	if haveHeader {
+		return plainReadOptions(delimiter, HeaderFirstRowRaw)
 	}
-	reader.FieldsPerRecord = -1 // 允许字段数不一致
+	return plainReadOptions(delimiter, HeaderNone)
+}
 
-	// 3. 读取所有行
-	// 使用 ReadAll 一次性读取，代码最简洁且性能足够好 ReadAll内部已经做好了缓冲处理。
-	allRecords, err := reader.ReadAll()
+// ReadCSV2RowsWithOptions 按读取选项读取 CSV 文件的列名和全部数据行，空文件返回 nil。
+func ReadCSV2RowsWithOptions(filePath string, opts ReadOptions) (header []string, rows [][]string, err error) {
+	src, err := openCSVSource(filePath, opts)
 	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
 		return nil, nil, fmt.Errorf("read csv failed: %w", err)
 	}
+	defer src.Close()
 
-	// 4. 处理空文件
-	if len(allRecords) == 0 {
-		return nil, nil, nil
-	}
-
-	// 5. 根据 haveHeader 拆分数据
-	if haveHeader {
-		// 第一行是表头
-		header = allRecords[0]
-		// 剩余行是数据
-		rows = allRecords[1:]
-	} else {
-		// 没有表头，全是数据
-		header = GenDefaultHeaders(len(allRecords[0]))
-		rows = allRecords
+	for {
+		row, err := src.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, nil, fmt.Errorf("read csv failed at line %d: %w", src.Line(), err)
+		}
+		rows = append(rows, row)
 	}
-
-	return header, rows, nil
+	return src.Header(), rows, nil
 }
 
 // ReadCSV2RowsWithSkip 读取 CSV 文件内容，支持跳过指定数量的数据行。
@@ -1,9 +1,10 @@
 package csvutils
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -11,16 +12,25 @@ import (
 // 参数:
 //
 //	csvFile: CSV 文件路径
-//	out: 必须是指向结构体切片的指针（例如 &[]User{}）
-//	tagName: 结构体字段标签名（例如 "csv"），用于匹配 CSV 表头
+//	out: 必须是指向结构体切片的指针（例如 &[]User{}），元素也可以是结构体指针
+//	tagName: 结构体字段标签名（例如 "csv"），用于匹配 CSV 表头，标签选项见 struct_fields.go
+//
+// 分隔符自动检测，字段不去除空白，不启用宽松引号，记录字段数与表头不一致时返回错误；
+// 需要其他解析规则时使用 ReadCSVToStructsWithOptions。
 func ReadCSVToStructs(csvFile string, out interface{}, tagName string) error {
-	// 1. 先调用原有函数读取 CSV 为字典格式
-	_, dicts, err := ReadCSVToDicts(csvFile)
-	if err != nil {
-		return fmt.Errorf("failed to read CSV to dicts: %w", err)
-	}
+	return ReadCSVToStructsWithOptions(csvFile, out, tagName, structsReadOptions())
+}
 
-	// 2. 验证 out 参数的类型（必须是指向结构体切片的指针）
+// structsReadOptions ReadCSVToStructs 使用的选项，在 GetCSVHeaders 的规则上要求字段数与表头一致
+func structsReadOptions() ReadOptions {
+	opts := plainHeaderReadOptions(0)
+	opts.FixedFields = true
+	return opts
+}
+
+// ReadCSVToStructsWithOptions 按读取选项逐行解码 CSV 并追加到结构体切片
+func ReadCSVToStructsWithOptions(csvFile string, out interface{}, tagName string, opts ReadOptions) error {
+	// 1. 验证 out 参数的类型（必须是指向结构体切片的指针）
 	outVal := reflect.ValueOf(out)
 	if outVal.Kind() != reflect.Ptr || outVal.IsNil() {
 		return fmt.Errorf("out must be a non-nil pointer to a slice of structs")
@@ -33,97 +43,178 @@ func ReadCSVToStructs(csvFile string, out interface{}, tagName string) error {
 
 	// 获取结构体的类型（切片的元素类型）
 	elemType := sliceVal.Type().Elem()
-	if elemType.Kind() != reflect.Struct {
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
 		return fmt.Errorf("slice element must be a struct (got %s)", elemType.Kind())
 	}
 
-	// 3. 构建表头到结构体字段的映射（支持 tag 匹配）
-	headerToField := make(map[string]int)
-	for i := 0; i < elemType.NumField(); i++ {
-		field := elemType.Field(i)
-		// 获取字段的 tag 值（例如 `csv:"name"` 中的 "name"）
-		tagValue := field.Tag.Get(tagName)
-		if tagValue == "" {
-			// 如果没有 tag，使用字段名（不区分大小写）
-			tagValue = field.Name
-		}
-		headerToField[strings.ToLower(tagValue)] = i
+	// 2. 逐行解码并追加到切片
+	decoder, err := NewStructDecoder(csvFile, tagName, opts)
+	if err != nil {
+		return err
 	}
+	defer decoder.Close()
 
-	// 4. 将每个字典转换为结构体实例
-	for dictIdx, dict := range dicts {
-		// 创建新的结构体实例
-		structVal := reflect.New(elemType).Elem()
-
-		// 遍历表头，给结构体字段赋值
-		for header, value := range dict {
-			headerLower := strings.ToLower(header)
-			fieldIdx, ok := headerToField[headerLower]
-			if !ok {
-				// 忽略不存在的字段（也可以选择返回错误）
-				continue
+	for {
+		item := reflect.New(structType)
+		if err := decoder.Decode(item.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
 			}
+			return err
+		}
+		if isPtr {
+			sliceVal.Set(reflect.Append(sliceVal, item))
+		} else {
+			sliceVal.Set(reflect.Append(sliceVal, item.Elem()))
+		}
+	}
+}
 
-			field := structVal.Field(fieldIdx)
-			if !field.CanSet() {
-				continue // 跳过不可设置的字段（例如私有字段）
-			}
+// ReadCSVToStructsWithCallback 逐行将 CSV 解码为结构体 T 并交给 callback 处理，适合读取大文件，
+// callback 返回错误时终止读取
+func ReadCSVToStructsWithCallback[T any](csvFile string, tagName string, opts ReadOptions, callback func(T) error) error {
+	if callback == nil {
+		return errors.New("callback function cannot be nil")
+	}
+	decoder, err := NewStructDecoder(csvFile, tagName, opts)
+	if err != nil {
+		return err
+	}
+	defer decoder.Close()
 
-			// 根据字段类型转换值
-			err := setFieldValue(field, value)
-			if err != nil {
-				return fmt.Errorf("row %d, field %s: %w", dictIdx+2, header, err)
+	for {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
 			}
+			return err
+		}
+		if err := callback(item); err != nil {
+			return fmt.Errorf("callback failed at row %d: %w", decoder.Line(), err)
 		}
-
-		// 将结构体添加到切片
-		sliceVal.Set(reflect.Append(sliceVal, structVal))
 	}
+}
 
-	return nil
+// StructDecoder 结构体解码器，逐行将 CSV 数据解码为结构体，列名与字段标签匹配时不区分大小写
+type StructDecoder struct {
+	src     *csvSource
+	tagName string
+	typ     reflect.Type
+	columns []*structField // 按列顺序排列的字段映射，没有对应字段的列为 nil
+	missing []structField  // 文件中没有对应列但设置了默认值的字段
 }
 
-// setFieldValue 根据字段类型设置值（支持常见类型）
-func setFieldValue(field reflect.Value, value string) error {
-	if value == "" {
-		return nil // 空值不设置
+// NewStructDecoder 打开 CSV 文件并创建结构体解码器，解析规则由 ReadOptions 决定
+func NewStructDecoder(csvFile string, tagName string, opts ReadOptions) (*StructDecoder, error) {
+	src, err := openCSVSource(csvFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("read header failed: %w", err)
 	}
+	return &StructDecoder{src: src, tagName: tagName}, nil
+}
 
-	switch field.Kind() {
-	case reflect.String:
-		field.SetString(value)
+// Header 返回列名
+func (d *StructDecoder) Header() []string {
+	return d.src.Header()
+}
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid int value: %w", err)
-		}
-		field.SetInt(val)
+// Line 返回最近读取的记录的起始物理行号
+func (d *StructDecoder) Line() int {
+	return d.src.Line()
+}
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		val, err := strconv.ParseUint(value, 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid uint value: %w", err)
-		}
-		field.SetUint(val)
+// Decode 读取下一行并解码到 out（指向结构体的指针），读取完毕时返回 io.EOF。
+// 解码前 out 会被重置为零值，空字段保持零值或使用标签中的默认值
+func (d *StructDecoder) Decode(out interface{}) error {
+	outVal := reflect.ValueOf(out)
+	if outVal.Kind() != reflect.Ptr || outVal.IsNil() || outVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("out must be a non-nil pointer to a struct")
+	}
+	structVal := outVal.Elem()
+	if structVal.Type() != d.typ {
+		d.bind(structVal.Type())
+	}
 
-	case reflect.Float32, reflect.Float64:
-		val, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return fmt.Errorf("invalid float value: %w", err)
+	row, err := d.src.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return err
 		}
-		field.SetFloat(val)
+		return fmt.Errorf("read row %d failed: %w", d.src.Line(), err)
+	}
 
-	case reflect.Bool:
-		val, err := strconv.ParseBool(value)
-		if err != nil {
-			return fmt.Errorf("invalid bool value: %w", err)
+	structVal.Set(reflect.Zero(d.typ))
+	for colIdx, field := range d.columns {
+		if field == nil {
+			continue
 		}
-		field.SetBool(val)
+		value := ""
+		if colIdx < len(row) {
+			value = row[colIdx]
+		}
+		if err := d.setField(structVal, field, value); err != nil {
+			return err
+		}
+	}
+	for i := range d.missing {
+		if err := d.setField(structVal, &d.missing[i], ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	default:
-		return fmt.Errorf("unsupported field type: %s", field.Kind())
+// Close 关闭底层文件
+func (d *StructDecoder) Close() error {
+	return d.src.Close()
+}
+
+// bind 建立列与结构体字段的映射关系
+func (d *StructDecoder) bind(typ reflect.Type) {
+	fields := cachedStructFields(typ, d.tagName)
+	byName := make(map[string]int, len(fields))
+	for i, f := range fields {
+		byName[strings.ToLower(f.name)] = i
 	}
 
+	header := d.src.Header()
+	d.typ = typ
+	d.columns = make([]*structField, len(header))
+	d.missing = nil
+	matched := make(map[int]bool, len(header))
+	for colIdx, name := range header {
+		if i, ok := byName[strings.ToLower(name)]; ok && !matched[i] {
+			matched[i] = true
+			d.columns[colIdx] = &fields[i]
+		}
+	}
+	for i, f := range fields {
+		if !matched[i] && f.hasDefault {
+			d.missing = append(d.missing, f)
+		}
+	}
+}
+
+// setField 将字段值或默认值写入结构体字段
+func (d *StructDecoder) setField(structVal reflect.Value, field *structField, value string) error {
+	if value == "" && field.hasDefault {
+		value = field.defaultVal
+	}
+	if value == "" {
+		return nil
+	}
+	target := fieldByIndexAlloc(structVal, field.index)
+	if !target.IsValid() || !target.CanSet() {
+		return nil // 跳过不可设置的字段（例如未导出的嵌入指针）
+	}
+	if err := decodeFieldValue(target, value, *field); err != nil {
+		return fmt.Errorf("row %d, field %s: %w", d.src.Line(), field.name, err)
+	}
 	return nil
 }
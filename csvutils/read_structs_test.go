@@ -249,3 +249,25 @@ func TestReadCSVToStructsSimpleInvalidType(t *testing.T) {
 		t.Error("期望错误，但没有返回错误")
 	}
 }
+
+// TestReadCSVToStructsLegacySemantics 测试 ReadCSVToStructs 保持原有的严格解析规则
+func TestReadCSVToStructsLegacySemantics(t *testing.T) {
+	filePath := makeTempCSV(t, "legacy_structs.csv", "name,age,email\n\" Alice \",30,a@example.com\n")
+	var users []User
+	if err := ReadCSVToStructs(filePath, &users, "csv"); err != nil {
+		t.Fatalf("read structs failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != " Alice " {
+		t.Errorf("expected untrimmed name, got %+v", users)
+	}
+
+	for name, content := range map[string]string{
+		"bare_quote":  "name,age,email\nAl\"ice,30,a@example.com\n",
+		"extra_field": "name,age,email\nAlice,30,a@example.com,extra\n",
+	} {
+		var out []User
+		if err := ReadCSVToStructs(makeTempCSV(t, name+".csv", content), &out, "csv"); err == nil {
+			t.Errorf("%s: expected error, got %+v", name, out)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package csvutils
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/winezer0/xutils/timeutils"
+)
+
+// 结构体标签格式：`csv:"name,omitempty,default=x,sep=;,layout=2006-01-02"`
+//   - name: 列名，为空时使用字段名，为 "-" 时忽略该字段
+//   - omitempty: 写入时零值输出为空字段
+//   - default=x: 读取时字段为空或缺失列时使用的默认值
+//   - sep=;: 切片字段的元素分隔符，默认为 ";"
+//   - layout=...: time.Time 字段的时间布局，读取时为空则依次尝试 TimeLayouts，写入时为空则使用 timeutils.LayoutISO8601
+
+// defaultSliceSep 切片字段默认的元素分隔符
+const defaultSliceSep = ";"
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// structField 结构体字段与列的映射关系
+type structField struct {
+	name       string
+	index      []int // 字段路径，嵌入结构体的字段包含多级下标
+	omitEmpty  bool
+	hasDefault bool
+	defaultVal string
+	sep        string
+	layout     string
+}
+
+// structFieldsCache 按结构体类型与标签名缓存字段映射
+var structFieldsCache sync.Map
+
+type structFieldsKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// cachedStructFields 获取结构体的字段映射，嵌入结构体的字段被展开，与 Go 的字段提升规则一致，外层字段优先
+func cachedStructFields(t reflect.Type, tagName string) []structField {
+	key := structFieldsKey{typ: t, tagName: tagName}
+	if fields, ok := structFieldsCache.Load(key); ok {
+		return fields.([]structField)
+	}
+	fields := collectStructFields(t, tagName, nil, map[reflect.Type]bool{})
+
+	// 同名字段保留层级最浅的一个
+	depth := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if d, ok := depth[strings.ToLower(f.name)]; !ok || len(f.index) < d {
+			depth[strings.ToLower(f.name)] = len(f.index)
+		}
+	}
+	result := make([]structField, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		key := strings.ToLower(f.name)
+		if len(f.index) != depth[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, f)
+	}
+	structFieldsCache.Store(key, result)
+	return result
+}
+
+func collectStructFields(t reflect.Type, tagName string, parent []int, visited map[reflect.Type]bool) []structField {
+	visited[t] = true
+	defer delete(visited, t)
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int(nil), parent...), i)
+		name, options := parseStructTag(tag)
+
+		// 未指定列名的嵌入结构体展开其字段
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType && !isTextType(ft) {
+			if !visited[ft] {
+				fields = append(fields, collectStructFields(ft, tagName, index, visited)...)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		field := structField{name: name, index: index, sep: defaultSliceSep}
+		for _, opt := range options {
+			switch {
+			case opt == "omitempty":
+				field.omitEmpty = true
+			case strings.HasPrefix(opt, "default="):
+				field.hasDefault = true
+				field.defaultVal = strings.TrimPrefix(opt, "default=")
+			case strings.HasPrefix(opt, "sep="):
+				if sep := strings.TrimPrefix(opt, "sep="); sep != "" {
+					field.sep = sep
+				}
+			case strings.HasPrefix(opt, "layout="):
+				field.layout = strings.TrimPrefix(opt, "layout=")
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// parseStructTag 拆分标签中的列名与选项
+func parseStructTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return strings.TrimSpace(parts[0]), parts[1:]
+}
+
+// isTextType 判断类型是否自行实现文本编解码，此类嵌入结构体作为单列处理
+func isTextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// fieldByIndexAlloc 按字段路径获取字段，路径上为 nil 的嵌入指针自动分配，无法分配时返回无效值
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// fieldByIndexRead 按字段路径获取字段，路径上的嵌入指针为 nil 时返回无效值
+func fieldByIndexRead(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// setFieldValue 根据字段类型设置值（支持常见类型）
+func setFieldValue(field reflect.Value, value string) error {
+	return decodeFieldValue(field, value, structField{sep: defaultSliceSep})
+}
+
+// decodeFieldValue 将字符串解析到字段，支持基础类型、指针、time.Time、切片与 encoding.TextUnmarshaler
+func decodeFieldValue(field reflect.Value, value string, opts structField) error {
+	if value == "" {
+		return nil // 空值不设置
+	}
+
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := decodeFieldValue(elem.Elem(), value, opts); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	if field.Type() == timeType {
+		layout := opts.layout
+		if layout == "" {
+			layout = detectTimeLayout(value)
+		}
+		t, err := time.Parse(layout, value)
+		if layout == "" || err != nil {
+			return fmt.Errorf("invalid time value: %s", value)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid %s value: %w", field.Type(), err)
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid int value: %w", err)
+		}
+		field.SetInt(val)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid uint value: %w", err)
+		}
+		field.SetUint(val)
+
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid float value: %w", err)
+		}
+		field.SetFloat(val)
+
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool value: %w", err)
+		}
+		field.SetBool(val)
+
+	case reflect.Slice:
+		parts := strings.Split(value, opts.sep)
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := decodeFieldValue(slice.Index(i), strings.TrimSpace(part), opts); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+
+	default:
+		return fmt.Errorf("unsupported field type: %s", field.Kind())
+	}
+
+	return nil
+}
+
+// encodeFieldValue 将字段值格式化为字符串，nil 指针与 omitempty 的零值输出为空字段
+func encodeFieldValue(field reflect.Value, opts structField) (string, error) {
+	if !field.IsValid() || (opts.omitEmpty && field.IsZero()) {
+		return "", nil
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", nil
+		}
+		return encodeFieldValue(field.Elem(), opts)
+	}
+
+	if field.Type() == timeType {
+		layout := opts.layout
+		if layout == "" {
+			layout = timeutils.LayoutISO8601
+		}
+		return field.Interface().(time.Time).Format(layout), nil
+	}
+	if marshaler, ok := textMarshaler(field); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("marshal %s value failed: %w", field.Type(), err)
+		}
+		return string(text), nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Slice:
+		parts := make([]string, field.Len())
+		for i := range parts {
+			part, err := encodeFieldValue(field.Index(i), structField{sep: opts.sep, layout: opts.layout})
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		return strings.Join(parts, opts.sep), nil
+	default:
+		return "", fmt.Errorf("unsupported field type: %s", field.Kind())
+	}
+}
+
+// textMarshaler 获取字段实现的 encoding.TextMarshaler，兼容指针接收者
+func textMarshaler(field reflect.Value) (encoding.TextMarshaler, bool) {
+	if field.Type().Implements(textMarshalerType) {
+		return field.Interface().(encoding.TextMarshaler), true
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textMarshalerType) {
+		return field.Addr().Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
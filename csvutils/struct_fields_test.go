@@ -0,0 +1,108 @@
+package csvutils
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte([]string{"", "low", "high"}[l]), nil
+}
+
+type Audit struct {
+	Created time.Time `csv:"created,layout=2006-01-02"`
+	Owner   string    `csv:"owner,default=admin"`
+}
+
+type Asset struct {
+	*Audit
+	Host   string   `csv:"host"`
+	Port   *int     `csv:"port,omitempty"`
+	Tags   []string `csv:"tags"`
+	Ports  []int    `csv:"ports,sep=|"`
+	Level  level    `csv:"level"`
+	Ignore string   `csv:"-"`
+}
+
+// TestStructsRoundTrip 测试嵌入结构体、指针、时间、切片与自定义文本类型的写入与读取
+func TestStructsRoundTrip(t *testing.T) {
+	port := 443
+	created := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	assets := []Asset{
+		{Audit: &Audit{Created: created, Owner: "alice"}, Host: "a.com", Port: &port, Tags: []string{"web", "prod"}, Ports: []int{80, 443}, Level: 2, Ignore: "x"},
+		{Audit: &Audit{}, Host: "b.com", Level: 1},
+	}
+
+	filePath := filepath.Join(t.TempDir(), "assets.csv")
+	if err := WriteStructsToCSV(filePath, assets, "csv", ',', true); err != nil {
+		t.Fatalf("write structs failed: %v", err)
+	}
+	_, rows, err := ReadCSV2Rows(filePath, ',', false)
+	if err != nil {
+		t.Fatalf("read rows failed: %v", err)
+	}
+	expectedRows := [][]string{
+		{"created", "owner", "host", "port", "tags", "ports", "level"},
+		{"2026-03-12", "alice", "a.com", "443", "web;prod", "80|443", "high"},
+		{"0001-01-01", "", "b.com", "", "", "", "low"},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Fatalf("unexpected rows: %q", rows)
+	}
+
+	var decoded []*Asset
+	if err := ReadCSVToStructs(filePath, &decoded, "csv"); err != nil {
+		t.Fatalf("read structs failed: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("unexpected decoded count: %d", len(decoded))
+	}
+	first, second := decoded[0], decoded[1]
+	if !first.Created.Equal(created) || first.Owner != "alice" || first.Port == nil || *first.Port != 443 {
+		t.Errorf("unexpected first asset: %+v %+v", first, first.Audit)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"web", "prod"}) || !reflect.DeepEqual(first.Ports, []int{80, 443}) || first.Level != 2 {
+		t.Errorf("unexpected first asset slices: %+v", first)
+	}
+	if second.Owner != "admin" || second.Port != nil || second.Tags != nil || second.Level != 1 {
+		t.Errorf("unexpected second asset: %+v %+v", second, second.Audit)
+	}
+}
+
+// TestReadCSVToStructsWithCallback 测试流式解码、缺失列默认值与错误行号
+func TestReadCSVToStructsWithCallback(t *testing.T) {
+	filePath := makeTempCSV(t, "assets.csv", "HOST,level\na.com,low\nb.com,bad\n")
+
+	var hosts []string
+	err := ReadCSVToStructsWithCallback(filePath, "csv", DefaultReadOptions(), func(a Asset) error {
+		if a.Audit == nil || a.Owner != "admin" {
+			t.Errorf("default value not applied: %+v", a.Audit)
+		}
+		hosts = append(hosts, a.Host)
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "row 3, field level") {
+		t.Errorf("expected level error at row 3, got %v", err)
+	}
+	if !reflect.DeepEqual(hosts, []string{"a.com"}) {
+		t.Errorf("unexpected hosts: %v", hosts)
+	}
+}
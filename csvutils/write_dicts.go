@@ -1,10 +1,7 @@
 package csvutils
 
 import (
-	"encoding/csv"
 	"fmt"
-	"github.com/winezer0/xutils/utils"
-	"os"
 )
 
 // WriteDictsToCSV 修复版：解决空分隔符+字段数不一致问题
@@ -12,14 +9,21 @@ import (
 // 1. 空分隔符默认设为逗号（避免 csv.Writer 报错）
 // 2. 兼容字段数不一致场景（CSV 读取时允许字段数不匹配）
 func WriteDictsToCSV(filePath string, dicts []map[string]interface{}, header []string, delimiter rune, overwrite bool) error {
+	opts := DefaultWriteOptions()
+	opts.Delimiter = delimiter
+	return WriteDictsToCSVWithOptions(filePath, dicts, header, overwrite, opts)
+}
+
+// WriteDictsToCSVWithOptions 按写入选项将字典列表写入 CSV 文件，可指定输出编码及 BOM
+func WriteDictsToCSVWithOptions(filePath string, dicts []map[string]interface{}, header []string, overwrite bool, opts WriteOptions) (err error) {
 	// 边界处理：无数据时直接返回
 	if len(dicts) == 0 {
 		return nil
 	}
 
-	// 修复1：空分隔符默认设为逗号（解决 invalid delimiter 错误）
-	if delimiter == 0 {
-		delimiter = ','
+	// 空分隔符默认设为逗号（解决 invalid delimiter 错误）
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
 	}
 
 	// 1. 处理表头：未指定则从第一个字典生成
@@ -34,43 +38,34 @@ func WriteDictsToCSV(filePath string, dicts []map[string]interface{}, header []s
 		return fmt.Errorf("convert dict list to rows failed: %w", err)
 	}
 
-	// 3. 确定文件打开模式
-	flag := utils.ParseFlagFromOver(overwrite)
+	// 3. 调用 ShouldWriteHeader 判断是否写表头（需在打开文件前判断，覆盖模式会清空文件）
+	needWriteHeader := shouldWriteHeader(filePath, usedHeader, overwrite, opts.headerReadOptions())
 
-	// 4. 打开文件
-	file, err := os.OpenFile(filePath, flag, 0644)
+	// 4. 打开文件并初始化 CSV Writer
+	writer, err := openCSVWriter(filePath, overwrite, opts)
 	if err != nil {
-		return fmt.Errorf("open file %s failed: %w", filePath, err)
+		return err
 	}
-	defer file.Close()
-
-	// 5. 初始化 CSV Writer
-	writer := csv.NewWriter(file)
-	writer.Comma = delimiter
-	defer writer.Flush()
-
-	// 6. 调用 ShouldWriteHeader 判断是否写表头
-	needWriteHeader := ShouldWriteHeader(filePath, usedHeader, overwrite, delimiter)
+	defer func() {
+		if cErr := writer.Close(); cErr != nil && err == nil {
+			err = fmt.Errorf("csv writer flush error for %s: %w", filePath, cErr)
+		}
+	}()
 
-	// 7. 写入表头（若需要）
+	// 5. 写入表头（若需要）
 	if needWriteHeader {
 		if err := writer.Write(usedHeader); err != nil {
 			return fmt.Errorf("write header to %s failed: %w", filePath, err)
 		}
 	}
 
-	// 8. 写入数据行
-	if len(rows) > 0 {
-		if err := writer.WriteAll(rows); err != nil {
+	// 6. 写入数据行
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("write data rows to %s failed: %w", filePath, err)
 		}
 	}
 
-	// 9. 检查 Writer 内部错误
-	if err := writer.Error(); err != nil {
-		return fmt.Errorf("csv writer flush error for %s: %w", filePath, err)
-	}
-
 	return nil
 }
 
@@ -0,0 +1,88 @@
+package csvutils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// WriteOptions CSV 写入选项
+type WriteOptions struct {
+	Delimiter rune   // 分隔符，0 表示逗号
+	Encoding  string // 输出编码，如 gbk、utf-16le，空串表示 utf-8
+	BOM       bool   // 写入新文件时在开头写入编码对应的 BOM，便于 Excel 识别编码
+	UseCRLF   bool   // 使用 \r\n 作为行尾
+}
+
+// DefaultWriteOptions 返回默认写入选项：逗号分隔、utf-8、不写 BOM
+func DefaultWriteOptions() WriteOptions {
+	return WriteOptions{Delimiter: ','}
+}
+
+// headerReadOptions 返回读取已有文件表头时使用的选项，编码与写入编码一致
+func (opts WriteOptions) headerReadOptions() ReadOptions {
+	readOpts := plainHeaderReadOptions(opts.Delimiter)
+	readOpts.Encoding = opts.Encoding
+	if readOpts.Encoding == "" {
+		readOpts.Encoding = "utf-8"
+	}
+	return readOpts
+}
+
+// csvFileWriter 按写入选项打开的 CSV 文件，负责编码转换与 BOM
+type csvFileWriter struct {
+	*csv.Writer
+	file    *os.File
+	encoder io.WriteCloser
+}
+
+// openCSVWriter 按覆盖模式打开 CSV 文件，文件为空时按选项写入 BOM，编码无法识别时不打开文件
+func openCSVWriter(filePath string, overwrite bool, opts WriteOptions) (*csvFileWriter, error) {
+	if err := utils.CheckEncode(opts.Encoding); err != nil {
+		return nil, fmt.Errorf("check write encoding failed: %w", err)
+	}
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	file, err := os.OpenFile(filePath, utils.ParseFlagFromOver(overwrite), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open file %s failed: %w", filePath, err)
+	}
+
+	if opts.BOM {
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("stat file %s failed: %w", filePath, err)
+		}
+		if bom := utils.EncodeBOM(opts.Encoding); info.Size() == 0 && len(bom) > 0 {
+			if _, err := file.Write(bom); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("write bom to %s failed: %w", filePath, err)
+			}
+		}
+	}
+
+	encoder := utils.NewEncodeWriter(file, opts.Encoding)
+	writer := csv.NewWriter(encoder)
+	writer.Comma = opts.Delimiter
+	writer.UseCRLF = opts.UseCRLF
+	return &csvFileWriter{Writer: writer, file: file, encoder: encoder}, nil
+}
+
+// Close 刷新缓冲区、编码转换器并关闭文件
+func (w *csvFileWriter) Close() error {
+	w.Flush()
+	err := w.Error()
+	if cErr := w.encoder.Close(); cErr != nil {
+		err = errors.Join(err, cErr)
+	}
+	if cErr := w.file.Close(); cErr != nil {
+		err = errors.Join(err, cErr)
+	}
+	return err
+}
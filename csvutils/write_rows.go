@@ -1,32 +1,31 @@
 package csvutils
 
-import (
-	"encoding/csv"
-	"github.com/winezer0/xutils/utils"
-	"os"
-)
-
 // WriteRowsToCSV 将表头与数据行写入 CSV 文件
 // 追加模式下会自动检测表头是否已存在，避免重复写入
 // 若表头不匹配则返回错误
 func WriteRowsToCSV(filePATH string, header []string, rows [][]string, delimiter rune, overwrite bool) error {
+	opts := DefaultWriteOptions()
+	opts.Delimiter = delimiter
+	return WriteRowsToCSVWithOptions(filePATH, header, rows, overwrite, opts)
+}
+
+// WriteRowsToCSVWithOptions 按写入选项将表头与数据行写入 CSV 文件，可指定输出编码及 BOM
+func WriteRowsToCSVWithOptions(filePATH string, header []string, rows [][]string, overwrite bool, opts WriteOptions) (err error) {
 	if len(rows) == 0 && len(header) == 0 {
 		return nil
 	}
 
-	needWriteHeader := ShouldWriteHeader(filePATH, header, overwrite, delimiter)
-
-	flag := utils.ParseFlagFromOver(overwrite)
+	needWriteHeader := shouldWriteHeader(filePATH, header, overwrite, opts.headerReadOptions())
 
-	file, err := os.OpenFile(filePATH, flag, 0644)
+	w, err := openCSVWriter(filePATH, overwrite, opts)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	w := csv.NewWriter(file)
-	w.Comma = delimiter
-	defer w.Flush()
+	defer func() {
+		if cErr := w.Close(); err == nil {
+			err = cErr
+		}
+	}()
 
 	if needWriteHeader && len(header) > 0 {
 		if err := w.Write(header); err != nil {
@@ -34,11 +33,10 @@ func WriteRowsToCSV(filePATH string, header []string, rows [][]string, delimiter
 		}
 	}
 
-	if len(rows) > 0 {
-		if err := w.WriteAll(rows); err != nil {
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
 			return err
 		}
 	}
-
-	return w.Error()
+	return nil
 }
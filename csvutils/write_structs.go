@@ -0,0 +1,72 @@
+package csvutils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WriteStructsToCSV 将结构体切片写入 CSV 文件，表头按字段顺序由 tagName 标签生成，嵌入结构体的字段被展开
+// items 必须是结构体切片或结构体指针切片，nil 指针元素被跳过
+func WriteStructsToCSV(filePath string, items interface{}, tagName string, delimiter rune, overwrite bool) error {
+	opts := DefaultWriteOptions()
+	opts.Delimiter = delimiter
+	return WriteStructsToCSVWithOptions(filePath, items, tagName, overwrite, opts)
+}
+
+// WriteStructsToCSVWithOptions 按写入选项将结构体切片写入 CSV 文件
+func WriteStructsToCSVWithOptions(filePath string, items interface{}, tagName string, overwrite bool, opts WriteOptions) error {
+	header, rows, err := StructsToRows(items, tagName)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return WriteRowsToCSVWithOptions(filePath, header, rows, overwrite, opts)
+}
+
+// StructsToRows 将结构体切片转换为表头与数据行，转换规则与 WriteStructsToCSV 一致
+func StructsToRows(items interface{}, tagName string) ([]string, [][]string, error) {
+	sliceVal := reflect.ValueOf(items)
+	if sliceVal.Kind() == reflect.Ptr && !sliceVal.IsNil() {
+		sliceVal = sliceVal.Elem()
+	}
+	if sliceVal.Kind() != reflect.Slice && sliceVal.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("items must be a slice of structs (got %s)", sliceVal.Kind())
+	}
+
+	structType := sliceVal.Type().Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("slice element must be a struct (got %s)", structType.Kind())
+	}
+
+	fields := cachedStructFields(structType, tagName)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+
+	rows := make([][]string, 0, sliceVal.Len())
+	for i := 0; i < sliceVal.Len(); i++ {
+		item := sliceVal.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		row := make([]string, len(fields))
+		for colIdx, f := range fields {
+			value, err := encodeFieldValue(fieldByIndexRead(item, f.index), f)
+			if err != nil {
+				return nil, nil, fmt.Errorf("item %d, field %s: %w", i, f.name, err)
+			}
+			row[colIdx] = value
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
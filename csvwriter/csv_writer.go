@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"encoding/csv"
 	"fmt"
-	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/liblog"
+	"github.com/winezer0/xutils/utils"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -13,9 +15,17 @@ import (
 // 写入超时时间
 const writeTimeout = 30 * time.Second
 
+// Options CSV 写入器选项
+type Options struct {
+	Delimiter rune   // 分隔符，0 表示逗号
+	Encoding  string // 输出编码，如 gbk、utf-16le，空串表示 utf-8
+	BOM       bool   // 新文件开头写入编码对应的 BOM，便于 Excel 识别编码
+}
+
 // CSVWriter 异步 CSV 写入器
 type CSVWriter struct {
 	file      *os.File
+	encoder   io.WriteCloser
 	bufWriter *bufio.Writer
 	writer    *csv.Writer
 	ch        chan []string
@@ -23,29 +33,49 @@ type CSVWriter struct {
 	headers   []string
 	closeOnce sync.Once
 	closed    bool
+	logger    liblog.Holder
 }
 
 // NewCSVWriter 创建异步 CSV 写入器
 func NewCSVWriter(filePath string, headers []string) (*CSVWriter, error) {
+	return NewCSVWriterWithOptions(filePath, headers, Options{})
+}
+
+// NewCSVWriterWithOptions 按选项创建异步 CSV 写入器，可指定分隔符、输出编码及 BOM
+func NewCSVWriterWithOptions(filePath string, headers []string, opts Options) (*CSVWriter, error) {
+	if err := utils.CheckEncode(opts.Encoding); err != nil {
+		return nil, fmt.Errorf("输出编码无效: %w", err)
+	}
 	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("打开 CSV 文件失败: %w", err)
 	}
 
-	bufWriter := bufio.NewWriter(f)
+	encoder := utils.NewEncodeWriter(f, opts.Encoding)
+	bufWriter := bufio.NewWriter(encoder)
 
 	w := &CSVWriter{
 		file:      f,
+		encoder:   encoder,
 		bufWriter: bufWriter,
 		writer:    csv.NewWriter(bufWriter),
 		ch:        make(chan []string, 1000),
 		done:      make(chan struct{}),
 		headers:   headers,
 	}
+	if opts.Delimiter != 0 {
+		w.writer.Comma = opts.Delimiter
+	}
 
-	// 检查文件是否为空，空文件需要写入表头
+	// 检查文件是否为空，空文件需要写入 BOM 和表头
 	fileInfo, err := f.Stat()
 	if err == nil && fileInfo.Size() == 0 {
+		if opts.BOM {
+			if _, err := f.Write(utils.EncodeBOM(opts.Encoding)); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("写入 BOM 失败: %w", err)
+			}
+		}
 		w.writer.Write(headers)
 		w.writer.Flush()
 		w.bufWriter.Flush()
@@ -61,11 +91,14 @@ func NewCSVWriter(filePath string, headers []string) (*CSVWriter, error) {
 func (w *CSVWriter) writeLoop() {
 	for record := range w.ch {
 		if err := w.writer.Write(record); err != nil {
-			logging.Warnf("CSV 写入失败: %v", err)
+			w.logger.GetOr(pkgLogger.Get()).Warnf("CSV 写入失败: %v", err)
 		}
 	}
 	w.writer.Flush()
 	w.bufWriter.Flush()
+	if err := w.encoder.Close(); err != nil {
+		w.logger.GetOr(pkgLogger.Get()).Warnf("CSV 编码转换刷新失败: %v", err)
+	}
 	close(w.done)
 }
 
@@ -82,6 +115,11 @@ func (w *CSVWriter) Write(record []string) error {
 	}
 }
 
+// SetLogger 设置该写入器的内部日志输出，传入 nil 时使用包级日志器
+func (w *CSVWriter) SetLogger(l liblog.Logger) {
+	w.logger.Set(l)
+}
+
 // Close 关闭写入器并刷新缓冲区（使用 sync.Once 防止重复关闭 channel 导致 panic）
 func (w *CSVWriter) Close() error {
 	var closeErr error
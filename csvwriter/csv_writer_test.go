@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/winezer0/xutils/utils"
 )
 
 // TestNewCSVWriter 创建写入器
@@ -303,3 +305,44 @@ func TestCSVReadBack(t *testing.T) {
 		}
 	}
 }
+
+// TestNewCSVWriterWithOptions 指定编码与 BOM
+func TestNewCSVWriterWithOptions(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "utf16.csv")
+	w, err := NewCSVWriterWithOptions(filePath, []string{"姓名", "城市"}, Options{Encoding: "utf-16le", BOM: true, Delimiter: ';'})
+	if err != nil {
+		t.Fatalf("创建写入器失败: %v", err)
+	}
+	if err := w.Write([]string{"张三", "北京"}); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("关闭写入器失败: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("读取文件失败: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "\xff\xfe") {
+		t.Fatalf("缺少 UTF-16LE BOM: % x", data[:4])
+	}
+	content, err := utils.NormalizedEncode("utf-16le").NewDecoder().Bytes(data[2:])
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if string(content) != "姓名;城市\n张三;北京\n" {
+		t.Errorf("内容不符: %q", content)
+	}
+}
+
+// TestNewCSVWriterUnknownEncoding 无法识别的编码直接报错，不创建文件
+func TestNewCSVWriterUnknownEncoding(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "enc.csv")
+	if _, err := NewCSVWriterWithOptions(filePath, []string{"姓名"}, Options{Encoding: "utf8x"}); err == nil {
+		t.Fatal("期望无法识别的编码返回错误")
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Errorf("不应创建文件: %v", err)
+	}
+}
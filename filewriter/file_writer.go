@@ -3,7 +3,7 @@ package filewriter
 import (
 	"bufio"
 	"fmt"
-	"github.com/winezer0/xutils/logging"
+	"github.com/winezer0/xutils/liblog"
 	"os"
 	"sync"
 	"time"
@@ -20,6 +20,7 @@ type FileWriter struct {
 	done      chan struct{}
 	closeOnce sync.Once
 	closed    bool
+	logger    liblog.Holder
 }
 
 // NewFileWriter 创建异步文本行写入器
@@ -45,7 +46,7 @@ func NewFileWriter(filePath string) (*FileWriter, error) {
 func (fw *FileWriter) writeLoop() {
 	for line := range fw.ch {
 		if _, err := fw.bufWriter.WriteString(line); err != nil {
-			logging.Warnf("文件写入失败: %v", err)
+			fw.logger.GetOr(pkgLogger.Get()).Warnf("文件写入失败: %v", err)
 		}
 	}
 	fw.bufWriter.Flush()
@@ -66,6 +67,11 @@ func (fw *FileWriter) Write(line string) error {
 	}
 }
 
+// SetLogger 设置该写入器的内部日志输出，传入 nil 时使用包级日志器
+func (fw *FileWriter) SetLogger(l liblog.Logger) {
+	fw.logger.Set(l)
+}
+
 // Close 关闭写入器并刷新缓冲区（使用 sync.Once 防止重复关闭 channel 和文件导致 panic）
 func (fw *FileWriter) Close() error {
 	var closeErr error
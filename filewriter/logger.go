@@ -0,0 +1,11 @@
+package filewriter
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger 未通过 FileWriter.SetLogger 单独设置时使用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置后台写入协程写入失败时的警告输出，对所有未单独设置日志器的 FileWriter 生效，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
@@ -2,6 +2,7 @@ package hashutils
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 )
 
@@ -12,6 +13,17 @@ func GetStrHashShort(s string) string {
 
 // GetStrHash 获取字符串的哈希值
 func GetStrHash(s string) string {
-	hash := md5.Sum([]byte(s))
+	return GetBytesHash([]byte(s))
+}
+
+// GetBytesHash 获取字节内容的 MD5 哈希值
+func GetBytesHash(data []byte) string {
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// GetBytesSHA256 获取字节内容的 SHA-256 哈希值
+func GetBytesSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
 	return hex.EncodeToString(hash[:])
 }
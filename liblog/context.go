@@ -0,0 +1,24 @@
+package liblog
+
+import "context"
+
+// ctxKey 上下文中保存日志器的键
+type ctxKey struct{}
+
+// WithContext 返回携带日志器的上下文，库函数通过 FromContext 取出并输出与该上下文相关的日志。
+func WithContext(ctx context.Context, l Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext 获取上下文中的日志器，未设置时返回 fallback。
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(Logger); ok && l != nil {
+			return l
+		}
+	}
+	return fallback
+}
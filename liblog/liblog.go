@@ -0,0 +1,62 @@
+package liblog
+
+import "sync/atomic"
+
+// Logger 库内部使用的最小日志接口，*logging.Logger 满足该接口。
+// 各包默认不输出任何日志，调用方可以通过各包的 SetLogger 按包注入，或按调用注入自己的实现，
+// 传入 logging.DefaultLibLogger() 可转发到默认日志器。
+type Logger interface {
+	Debugf(template string, args ...interface{})
+	Infof(template string, args ...interface{})
+	Warnf(template string, args ...interface{})
+	Errorf(template string, args ...interface{})
+}
+
+// nopLogger 丢弃所有日志
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// Nop 返回不输出任何内容的日志器。
+func Nop() Logger {
+	return nopLogger{}
+}
+
+// Or 返回 l，l 为 nil 时返回 fallback。
+func Or(l Logger, fallback Logger) Logger {
+	if l != nil {
+		return l
+	}
+	return fallback
+}
+
+// Holder 并发安全地保存可替换的日志器，零值返回 Nop。
+type Holder struct {
+	value atomic.Value
+}
+
+// box 统一 atomic.Value 中保存的具体类型
+type box struct {
+	logger Logger
+}
+
+// Set 替换日志器，传入 nil 时恢复为未设置状态。
+func (h *Holder) Set(l Logger) {
+	h.value.Store(box{logger: l})
+}
+
+// Get 获取当前日志器，未设置时返回 Nop。
+func (h *Holder) Get() Logger {
+	return h.GetOr(nopLogger{})
+}
+
+// GetOr 获取当前日志器，未设置时返回 fallback。
+func (h *Holder) GetOr(fallback Logger) Logger {
+	if b, ok := h.value.Load().(box); ok && b.logger != nil {
+		return b.logger
+	}
+	return fallback
+}
@@ -0,0 +1,62 @@
+package liblog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type recordLogger struct {
+	lines []string
+}
+
+func (r *recordLogger) Debugf(t string, a ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(t, a...))
+}
+func (r *recordLogger) Infof(t string, a ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(t, a...))
+}
+func (r *recordLogger) Warnf(t string, a ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(t, a...))
+}
+func (r *recordLogger) Errorf(t string, a ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(t, a...))
+}
+
+// TestHolder 验证零值为 Nop，设置后转发到注入的日志器，设置 nil 后恢复 Nop。
+func TestHolder(t *testing.T) {
+	var holder Holder
+	holder.Get().Warnf("dropped")
+
+	rec := &recordLogger{}
+	holder.Set(rec)
+	holder.Get().Warnf("row %d", 3)
+	if len(rec.lines) != 1 || rec.lines[0] != "row 3" {
+		t.Fatalf("unexpected lines: %v", rec.lines)
+	}
+
+	holder.Set(nil)
+	holder.Get().Warnf("dropped")
+	if len(rec.lines) != 1 {
+		t.Fatalf("expected nop after reset, got: %v", rec.lines)
+	}
+	if holder.GetOr(rec) != rec {
+		t.Fatal("expected fallback logger when unset")
+	}
+	if Or(nil, rec) != rec {
+		t.Fatal("expected fallback logger")
+	}
+}
+
+// TestContext 验证上下文中的日志器优先于 fallback。
+func TestContext(t *testing.T) {
+	rec := &recordLogger{}
+	if FromContext(context.Background(), rec) != rec {
+		t.Fatal("expected fallback")
+	}
+	ctx := WithContext(context.Background(), rec)
+	FromContext(ctx, Nop()).Infof("hello %d", 1)
+	if len(rec.lines) != 1 || rec.lines[0] != "hello 1" {
+		t.Fatalf("unexpected lines: %v", rec.lines)
+	}
+}
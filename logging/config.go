@@ -1,14 +1,35 @@
 package logging
 
+import "time"
+
 // LogConfig 日志配置结构体
 type LogConfig struct {
-	Level         string // 日志级别: debug/info/warn/error/fatal
-	LogFile       string // 日志文件路径，空串表示不输出到文件
-	ConsoleFormat string // 控制台格式: 空串或"off"表示关闭，支持"T(时间)L(级别)C(调用者)M(消息)"
-	MaxSize       int    // 单个日志文件文件最大大小
-	MaxBackups    int    // 最多保留几个日志文件备份
-	MaxAge        int    // 日志文件保留多少天
-	Compress      bool   // 日志备份文件是否压缩
+	Level         string `yaml:"level" json:"level"`                   // 日志级别: debug/info/warn/error/fatal
+	LogFile       string `yaml:"log_file" json:"log_file"`             // 日志文件路径，空串表示不输出到文件
+	ConsoleFormat string `yaml:"console_format" json:"console_format"` // 控制台格式: 空串或"off"表示关闭，支持"T(时间)L(级别)C(调用者)M(消息)"
+	MaxSize       int    `yaml:"max_size" json:"max_size"`             // 单个日志文件文件最大大小
+	MaxBackups    int    `yaml:"max_backups" json:"max_backups"`       // 最多保留几个日志文件备份
+	MaxAge        int    `yaml:"max_age" json:"max_age"`               // 日志文件保留多少天
+	Compress      bool   `yaml:"compress" json:"compress"`             // 日志备份文件是否压缩
+
+	ConsoleLevel      string `yaml:"console_level" json:"console_level"`             // 控制台日志级别，与 Level 同时生效，空串表示只按 Level 过滤
+	ConsoleEncoding   string `yaml:"console_encoding" json:"console_encoding"`       // 控制台编码: 空串或"console"为文本，"json"为JSON
+	ConsoleOutput     string `yaml:"console_output" json:"console_output"`           // 控制台输出目标: 空串或"stdout"为标准输出，"stderr"为标准错误
+	ConsoleColor      bool   `yaml:"console_color" json:"console_color"`             // 控制台为终端时以彩色输出日志级别
+	ConsoleTimeLayout string `yaml:"console_time_layout" json:"console_time_layout"` // 控制台时间格式(如"2006-01-02 15:04:05")，空串表示ISO8601
+	FileLevel         string `yaml:"file_level" json:"file_level"`                   // 文件日志级别，与 Level 同时生效，空串表示只按 Level 过滤
+	FileEncoding      string `yaml:"file_encoding" json:"file_encoding"`             // 文件编码: 空串或"json"为JSON，"console"为文本
+	FileTimeLayout    string `yaml:"file_time_layout" json:"file_time_layout"`       // 文件时间格式，空串表示ISO8601
+
+	Outputs []OutputConfig `yaml:"outputs" json:"outputs"` // 额外的输出目标，与上面的控制台和文件输出同时生效
+
+	// 采样与限流按级别与消息内容区分消息，格式化参数不同的日志视为不同消息，热点日志应使用固定消息并以字段携带变量。
+	// 丢弃条数在下一条输出的日志之前报告（每个周期最多一次），剩余的在 Sync/CloseAll 时报告
+	SampleInitial    int           `yaml:"sample_initial" json:"sample_initial"`       // 每个采样周期内同一消息先完整输出的条数，0 表示不采样
+	SampleThereafter int           `yaml:"sample_thereafter" json:"sample_thereafter"` // 超出 SampleInitial 后每 M 条输出一条，0 表示全部丢弃
+	SampleInterval   time.Duration `yaml:"sample_interval" json:"sample_interval"`     // 采样周期，0 表示1秒
+	RateLimit        int           `yaml:"rate_limit" json:"rate_limit"`               // 同一消息每个限流周期内最多输出的条数，0 表示不限流
+	RateInterval     time.Duration `yaml:"rate_interval" json:"rate_interval"`         // 限流周期，0 表示1秒
 }
 
 // NewLogConfig 创建日志配置实例，提供默认值
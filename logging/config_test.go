@@ -1,6 +1,12 @@
 package logging
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 // TestNewLogConfig 测试日志配置创建
 func TestNewLogConfig(t *testing.T) {
@@ -53,3 +59,73 @@ func TestNewLogConfigEmpty(t *testing.T) {
 		t.Error("Expected compress to be true")
 	}
 }
+
+// TestSinkLevelsAndEncodings 测试控制台与文件分别使用独立的级别、编码和时间格式
+func TestSinkLevelsAndEncodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	logFile := filepath.Join(tmpDir, "sink.log")
+	stderrFile, err := os.Create(filepath.Join(tmpDir, "stderr.txt"))
+	if err != nil {
+		t.Fatalf("Failed to create stderr file: %v", err)
+	}
+	defer stderrFile.Close()
+	originStderr := os.Stderr
+	os.Stderr = stderrFile
+	defer func() {
+		os.Stderr = originStderr
+	}()
+
+	config := NewLogConfig("debug", logFile, "LM")
+	config.ConsoleOutput = "stderr"
+	config.ConsoleLevel = "warn"
+	config.FileLevel = "debug"
+	config.FileEncoding = "console"
+	config.FileTimeLayout = "2006/01/02"
+	logger, err := CreateLogger("sinks", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	logger.Debugf("debug detail")
+	logger.Warnf("warn message")
+	// 运行期提高主级别后同样作用于设置了独立级别的输出
+	if err := logger.SetLevel("error"); err != nil {
+		t.Fatalf("Failed to set level: %v", err)
+	}
+	logger.Warnf("suppressed warn")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Failed to sync logger: %v", err)
+	}
+
+	consoleData, _ := os.ReadFile(stderrFile.Name())
+	if string(consoleData) != "WARN\twarn message\n" {
+		t.Errorf("Unexpected console output: %q", string(consoleData))
+	}
+	fileData, _ := os.ReadFile(logFile)
+	lines := strings.Split(strings.TrimSpace(string(fileData)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 file lines, got %q", string(fileData))
+	}
+	prefix := time.Now().Format("2006/01/02") + "\tDEBUG\t"
+	if !strings.HasPrefix(lines[0], prefix) || !strings.HasSuffix(lines[0], "debug detail") {
+		t.Errorf("Unexpected file line: %q", lines[0])
+	}
+}
+
+// TestInvalidSinkConfig 测试无效的输出配置返回错误
+func TestInvalidSinkConfig(t *testing.T) {
+	config := NewLogConfig("info", "", "LM")
+	config.ConsoleOutput = "printer"
+	if _, err := CreateLogger("invalid_output", config); err == nil {
+		t.Error("Expected error for invalid console output")
+	}
+
+	config = NewLogConfig("info", "", "LM")
+	config.ConsoleEncoding = "xml"
+	if _, err := CreateLogger("invalid_encoding", config); err == nil {
+		t.Error("Expected error for invalid console encoding")
+	}
+}
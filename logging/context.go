@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/winezer0/xutils/liblog"
+	"go.uber.org/zap"
+)
+
+// -------------------------- 上下文日志器 --------------------------
+
+// TraceIDKey 追踪ID在日志中的字段名
+const TraceIDKey = "trace_id"
+
+// traceIDCtxKey 上下文中保存追踪ID的键
+type traceIDCtxKey struct{}
+
+// NewTraceID 生成随机的追踪ID
+func NewTraceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// WithTraceID 返回携带追踪ID的上下文，如使用上游请求传入的请求ID，需在 WithContext 之前调用
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceIDCtxKey{}, traceID)
+}
+
+// TraceID 获取上下文中的追踪ID，未设置时返回空串
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDCtxKey{}).(string)
+	return traceID
+}
+
+// WithContext 返回携带日志器的上下文，日志器自动附带追踪ID字段，上下文中没有追踪ID时自动生成。
+// 日志器同时以 liblog.Logger 的形式保存，库内部的 CSV 消费者、写盘池等会使用该日志器输出与上下文相关的日志
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if logger == nil {
+		if logger = getDefaultLogger(); logger == nil {
+			logger = &Logger{}
+		}
+	}
+	traceID := TraceID(ctx)
+	if traceID == "" {
+		traceID = NewTraceID()
+		ctx = WithTraceID(ctx, traceID)
+	}
+	return liblog.WithContext(ctx, logger.With(String(TraceIDKey, traceID)))
+}
+
+// ContextWith 返回上下文日志器附加固定字段后的新上下文，如目标地址、任务ID等
+func ContextWith(ctx context.Context, fields ...Field) context.Context {
+	return liblog.WithContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// FromContext 获取上下文中的日志器，未设置时返回默认日志器，上下文中有追踪ID时附带追踪ID字段
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := liblog.FromContext(ctx, nil).(*Logger); ok {
+		return logger
+	}
+	logger := getDefaultLogger()
+	if logger == nil {
+		return &Logger{}
+	}
+	if traceID := TraceID(ctx); traceID != "" {
+		return logger.With(String(TraceIDKey, traceID))
+	}
+	return logger
+}
+
+// ctxLogger 获取上下文日志器并额外跳过一层调用栈，供 *Ctx 函数使用
+func ctxLogger(ctx context.Context) *Logger {
+	return FromContext(ctx).derive(zap.AddCallerSkip(1))
+}
+
+// 上下文日志函数，输出时附带上下文中保存的字段
+func DebugfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Debugf(template, args...)
+}
+
+func InfofCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Infof(template, args...)
+}
+
+func WarnfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Warnf(template, args...)
+}
+
+func ErrorfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Errorf(template, args...)
+}
+
+func DebugwCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	ctxLogger(ctx).Debugw(msg, keysAndValues...)
+}
+
+func InfowCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	ctxLogger(ctx).Infow(msg, keysAndValues...)
+}
+
+func WarnwCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	ctxLogger(ctx).Warnw(msg, keysAndValues...)
+}
+
+func ErrorwCtx(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	ctxLogger(ctx).Errorw(msg, keysAndValues...)
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestContextLogger 测试上下文日志器自动附带追踪ID及上下文字段
+func TestContextLogger(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "context.log")
+	logger, err := CreateLogger("context", NewLogConfig("debug", logFile, "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	ctx := WithContext(context.Background(), logger)
+	traceID := TraceID(ctx)
+	if traceID == "" {
+		t.Fatal("Expected trace id to be generated")
+	}
+	ctx = ContextWith(ctx, String("target", "a.com"))
+	InfofCtx(ctx, "scan %s", "start")
+	WarnwCtx(ctx, "slow", "cost", 3)
+
+	// 使用上游传入的请求ID
+	reqCtx := WithContext(WithTraceID(context.Background(), "req-1"), logger)
+	FromContext(reqCtx).Info("request")
+	_ = logger.Sync()
+
+	records := readJSONLogLines(t, logFile)
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	for _, record := range records[:2] {
+		if record[TraceIDKey] != traceID || record["target"] != "a.com" {
+			t.Errorf("Unexpected record: %v", record)
+		}
+	}
+	if caller, _ := records[0]["caller"].(string); !strings.HasPrefix(caller, "logging/context_test.go") {
+		t.Errorf("Expected caller in context_test.go, got %q", caller)
+	}
+	if records[1]["cost"] != float64(3) {
+		t.Errorf("Unexpected record: %v", records[1])
+	}
+	if records[2][TraceIDKey] != "req-1" {
+		t.Errorf("Expected request id, got %v", records[2])
+	}
+}
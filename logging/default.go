@@ -3,16 +3,76 @@ package logging
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+
+	"github.com/winezer0/xutils/liblog"
+	"go.uber.org/zap"
 )
 
-var defaultLogger *Logger       // 旧版本默认日志器
-var defaultLoggerOnce sync.Once // 用于确保 defaultLogger 只初始化一次
+// defaultLoggers 默认日志器及供包级函数使用的派生日志器，二者总是一起替换
+type defaultLoggers struct {
+	logger *Logger // 旧版本默认日志器
+	pkg    *Logger // 额外跳过一层调用栈以显示真实调用位置
+}
+
+var defaultState atomic.Pointer[defaultLoggers] // 包级函数无锁读取
+var defaultLoggerMu sync.Mutex                  // 串行化默认日志器的初始化与替换
 
 // InitDefaultLogger 旧版本初始化函数，兼容老代码
 func InitDefaultLogger(config LogConfig) error {
-	var err error
-	defaultLogger, err = CreateLogger("default", config)
-	return err
+	logger, err := CreateLogger("default", config)
+	if err != nil {
+		return err
+	}
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	storeDefaultLogger(logger)
+	return nil
+}
+
+// storeDefaultLogger 设置默认日志器及包级函数使用的派生日志器，调用方需持有 defaultLoggerMu
+func storeDefaultLogger(logger *Logger) {
+	if logger == nil {
+		defaultState.Store(nil)
+		return
+	}
+	defaultState.Store(&defaultLoggers{logger: logger, pkg: logger.derive(zap.AddCallerSkip(1))})
+}
+
+// loadDefaultLogger 返回当前默认日志器，未初始化时返回 nil
+func loadDefaultLogger() *Logger {
+	if state := defaultState.Load(); state != nil {
+		return state.logger
+	}
+	return nil
+}
+
+// SetDefaultLogger 替换包级函数使用的默认日志器并返回原默认日志器，logger 为 nil 时恢复为未初始化状态，
+// 下次调用包级函数时重新自动初始化
+func SetDefaultLogger(logger *Logger) *Logger {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	previous := loadDefaultLogger()
+	storeDefaultLogger(logger)
+	return previous
+}
+
+// resetDefaultLogger 默认日志器被关闭时清除，包级函数下次调用时重新自动初始化
+func resetDefaultLogger(logger *Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if loadDefaultLogger() == logger {
+		storeDefaultLogger(nil)
+	}
+}
+
+// replaceDefaultLogger 默认日志器被替换时切换到新的日志器
+func replaceDefaultLogger(old, logger *Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if loadDefaultLogger() == old {
+		storeDefaultLogger(logger)
+	}
 }
 
 // NewDefaultLogger 旧版本初始化函数，兼容老代码
@@ -20,93 +80,209 @@ func NewDefaultLogger(level, logFile, consoleFormat string) error {
 	return InitDefaultLogger(NewLogConfig(level, logFile, consoleFormat))
 }
 
-// ensureDefaultLogger 确保 defaultLogger 已初始化，如果未初始化则自动初始化（线程安全）
-func ensureDefaultLogger() {
-	if defaultLogger == nil {
-		defaultLoggerOnce.Do(func() {
-			if err := InitDefaultLogger(NewLogConfigEmpty()); err != nil {
-				fmt.Printf("init logger error: %v\n", err)
-			}
-		})
+// ensureDefaultLogger 确保默认日志器已初始化并返回，未初始化时自动初始化（线程安全），
+// 管理器中仍有 default 日志器时（如 SetDefaultLogger(nil) 之后）直接复用，初始化失败时返回 nil
+func ensureDefaultLogger() *defaultLoggers {
+	if state := defaultState.Load(); state != nil {
+		return state
 	}
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if state := defaultState.Load(); state != nil {
+		return state
+	}
+	logger, err := GetOrCreateLogger("default", NewLogConfigEmpty())
+	if err != nil {
+		fmt.Printf("init logger error: %v\n", err)
+		return nil
+	}
+	storeDefaultLogger(logger)
+	return defaultState.Load()
+}
+
+// getDefaultLogger 返回默认日志器，必要时自动初始化
+func getDefaultLogger() *Logger {
+	if state := ensureDefaultLogger(); state != nil {
+		return state.logger
+	}
+	return nil
+}
+
+// getDefaultPkgLogger 返回包级函数使用的派生日志器，必要时自动初始化
+func getDefaultPkgLogger() *Logger {
+	if state := ensureDefaultLogger(); state != nil {
+		return state.pkg
+	}
+	return nil
 }
 
 // Sync 旧版本全局刷新函数
 func Sync() error {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		return defaultLogger.Sync()
+	if logger := getDefaultLogger(); logger != nil {
+		return logger.Sync()
 	}
 	return nil
 }
 
 // 旧版本全局日志函数，直接转发到 default 日志器
 func Debugf(template string, args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Debugf(template, args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Debugf(template, args...)
 	}
 }
 
 func Infof(template string, args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Infof(template, args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Infof(template, args...)
 	}
 }
 
 func Warnf(template string, args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Warnf(template, args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Warnf(template, args...)
 	}
 }
 
 func Errorf(template string, args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Errorf(template, args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Errorf(template, args...)
 	}
 }
 
 func Fatalf(template string, args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Fatalf(template, args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Fatalf(template, args...)
 	}
 }
 
 func Debug(args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Debug(args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Debug(args...)
 	}
 }
 
 func Info(args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Info(args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Info(args...)
 	}
 }
 
 func Warn(args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Warn(args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Warn(args...)
 	}
 }
 
 func Error(args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Error(args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Error(args...)
 	}
 }
 
 func Fatal(args ...interface{}) {
-	ensureDefaultLogger()
-	if defaultLogger != nil {
-		defaultLogger.Fatal(args...)
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Fatal(args...)
+	}
+}
+
+// With 基于默认日志器派生携带固定字段的子日志器
+func With(fields ...Field) *Logger {
+	if logger := getDefaultLogger(); logger != nil {
+		return logger.With(fields...)
+	}
+	return &Logger{}
+}
+
+// 默认日志器的结构化日志函数，keysAndValues 为交替出现的键和值
+func Debugw(msg string, keysAndValues ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Debugw(msg, keysAndValues...)
+	}
+}
+
+func Infow(msg string, keysAndValues ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Infow(msg, keysAndValues...)
 	}
 }
+
+func Warnw(msg string, keysAndValues ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Warnw(msg, keysAndValues...)
+	}
+}
+
+func Errorw(msg string, keysAndValues ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Errorw(msg, keysAndValues...)
+	}
+}
+
+func Fatalw(msg string, keysAndValues ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Fatalw(msg, keysAndValues...)
+	}
+}
+
+// 默认日志器的强类型字段日志函数
+func DebugFields(msg string, fields ...Field) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.DebugFields(msg, fields...)
+	}
+}
+
+func InfoFields(msg string, fields ...Field) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.InfoFields(msg, fields...)
+	}
+}
+
+func WarnFields(msg string, fields ...Field) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.WarnFields(msg, fields...)
+	}
+}
+
+func ErrorFields(msg string, fields ...Field) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.ErrorFields(msg, fields...)
+	}
+}
+
+func FatalFields(msg string, fields ...Field) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.FatalFields(msg, fields...)
+	}
+}
+
+// defaultLibLogger 将库内部日志转发到默认日志器
+type defaultLibLogger struct{}
+
+func (defaultLibLogger) Debugf(template string, args ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Debugf(template, args...)
+	}
+}
+
+func (defaultLibLogger) Infof(template string, args ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Infof(template, args...)
+	}
+}
+
+func (defaultLibLogger) Warnf(template string, args ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Warnf(template, args...)
+	}
+}
+
+func (defaultLibLogger) Errorf(template string, args ...interface{}) {
+	if logger := getDefaultPkgLogger(); logger != nil {
+		logger.Errorf(template, args...)
+	}
+}
+
+// DefaultLibLogger 返回转发到默认日志器的库日志器，传给各包的 SetLogger 可恢复旧版本的输出行为
+func DefaultLibLogger() liblog.Logger {
+	return defaultLibLogger{}
+}
@@ -1,6 +1,11 @@
 package logging
 
-import "testing"
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
 
 // TestInitDefaultLogger 测试初始化默认日志器
 func TestInitDefaultLogger(t *testing.T) {
@@ -14,7 +19,7 @@ func TestInitDefaultLogger(t *testing.T) {
 	}()
 
 	// 验证默认日志器已创建
-	if defaultLogger == nil {
+	if loadDefaultLogger() == nil {
 		t.Error("Expected defaultLogger to be initialized")
 	}
 }
@@ -45,7 +50,7 @@ func TestEnsureDefaultLogger(t *testing.T) {
 	Info("Test auto-init message")
 
 	// 验证 defaultLogger 已被初始化
-	if defaultLogger == nil {
+	if loadDefaultLogger() == nil {
 		t.Error("Expected defaultLogger to be auto-initialized")
 	}
 
@@ -72,7 +77,107 @@ func TestGlobalLogFunctions(t *testing.T) {
 	Debug("Auto-init debug")
 
 	// 验证默认日志器已创建
-	if defaultLogger == nil {
+	if loadDefaultLogger() == nil {
 		t.Error("Expected defaultLogger to be auto-initialized")
 	}
 }
+
+// TestDefaultWith 测试默认日志器的结构化函数与子日志器
+func TestDefaultWith(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "default_with.log")
+	if err := InitDefaultLogger(NewLogConfig("debug", logFile, "off")); err != nil {
+		t.Fatalf("Failed to init logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	Infow("default message", "target", "a.com")
+	With(String("module", "csv")).InfoFields("child message", Int64("rows", 10))
+	_ = Sync()
+
+	records := readJSONLogLines(t, logFile)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0]["target"] != "a.com" {
+		t.Errorf("Unexpected record: %v", records[0])
+	}
+	if caller, _ := records[0]["caller"].(string); !strings.HasPrefix(caller, "logging/default_test.go") {
+		t.Errorf("Expected caller in default_test.go, got %q", caller)
+	}
+	if records[1]["module"] != "csv" || records[1]["rows"] != float64(10) {
+		t.Errorf("Unexpected child record: %v", records[1])
+	}
+}
+
+// TestDefaultSetLevel 测试运行期修改默认日志器级别
+func TestDefaultSetLevel(t *testing.T) {
+	if err := InitDefaultLogger(NewLogConfig("info", filepath.Join(t.TempDir(), "default_level.log"), "off")); err != nil {
+		t.Fatalf("Failed to init logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	if err := SetLevel("error"); err != nil {
+		t.Fatalf("Failed to set level: %v", err)
+	}
+	if GetLevel() != "error" {
+		t.Errorf("Expected level error, got %s", GetLevel())
+	}
+}
+
+// TestDefaultLoggerConcurrentRemove 测试包级函数与移除默认日志器并发执行
+func TestDefaultLoggerConcurrentRemove(t *testing.T) {
+	defer func() {
+		_ = CloseAll()
+	}()
+	if err := InitDefaultLogger(NewLogConfig("info", filepath.Join(t.TempDir(), "concurrent.log"), "off")); err != nil {
+		t.Fatalf("Failed to init logger: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				Debugf("concurrent %d", j)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		_ = RemoveLogger("default")
+		Infof("re-init %d", i)
+	}
+	wg.Wait()
+
+	logger := loadDefaultLogger()
+	if logger == nil {
+		t.Fatal("Expected default logger to be re-initialized")
+	}
+	if managed, _ := GetLogger("default"); managed != logger {
+		t.Error("Expected default logger to be the managed default logger")
+	}
+}
+
+// TestSetDefaultLoggerNil 测试恢复为未初始化状态后重新自动初始化
+func TestSetDefaultLoggerNil(t *testing.T) {
+	defer func() {
+		_ = CloseAll()
+	}()
+	Infof("auto-init")
+	auto := loadDefaultLogger()
+	if auto == nil {
+		t.Fatal("Expected defaultLogger to be auto-initialized")
+	}
+
+	if previous := SetDefaultLogger(nil); previous != auto {
+		t.Error("Expected SetDefaultLogger to return the auto-initialized logger")
+	}
+	Infof("re-init")
+	if loadDefaultLogger() != auto {
+		t.Error("Expected the managed default logger to be reused")
+	}
+}
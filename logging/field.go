@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// -------------------------- 结构化字段 --------------------------
+
+// Field 结构化日志字段，文件输出中会作为独立的 JSON 键保存
+type Field = zap.Field
+
+// String 创建字符串字段
+func String(key string, value string) Field {
+	return zap.String(key, value)
+}
+
+// Strings 创建字符串切片字段
+func Strings(key string, values []string) Field {
+	return zap.Strings(key, values)
+}
+
+// Int 创建整数字段
+func Int(key string, value int) Field {
+	return zap.Int(key, value)
+}
+
+// Int64 创建 int64 字段
+func Int64(key string, value int64) Field {
+	return zap.Int64(key, value)
+}
+
+// Float64 创建浮点数字段
+func Float64(key string, value float64) Field {
+	return zap.Float64(key, value)
+}
+
+// Bool 创建布尔字段
+func Bool(key string, value bool) Field {
+	return zap.Bool(key, value)
+}
+
+// Duration 创建时长字段
+func Duration(key string, value time.Duration) Field {
+	return zap.Duration(key, value)
+}
+
+// Time 创建时间字段
+func Time(key string, value time.Time) Field {
+	return zap.Time(key, value)
+}
+
+// Err 创建键名为 error 的错误字段
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// Any 根据值的实际类型创建字段
+func Any(key string, value interface{}) Field {
+	return zap.Any(key, value)
+}
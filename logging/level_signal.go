@@ -0,0 +1,36 @@
+//go:build !windows
+
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ToggleDebugOnSignal 收到 SIGUSR1 时在 debug 与配置级别之间切换，返回停止函数
+func (l *Logger) ToggleDebugOnSignal() (stop func(), err error) {
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigCh, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigCh:
+				l.toggleDebug()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}, nil
+}
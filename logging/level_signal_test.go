@@ -0,0 +1,31 @@
+//go:build !windows
+
+package logging
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestToggleDebugOnSignal 测试 SIGUSR1 在 debug 与配置级别之间切换
+func TestToggleDebugOnSignal(t *testing.T) {
+	logger, err := CreateLogger("level_signal", NewLogConfig("info", filepath.Join(t.TempDir(), "signal.log"), "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	stop, err := logger.ToggleDebugOnSignal()
+	if err != nil {
+		t.Fatalf("Failed to enable signal toggle: %v", err)
+	}
+	defer stop()
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(t, logger, "debug")
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(t, logger, "info")
+}
@@ -0,0 +1,8 @@
+package logging
+
+import "fmt"
+
+// ToggleDebugOnSignal Windows 不支持 SIGUSR1，请改用 WatchLevelFile
+func (l *Logger) ToggleDebugOnSignal() (stop func(), err error) {
+	return func() {}, fmt.Errorf("signal level toggle is not supported on windows, use WatchLevelFile instead")
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------- 运行期级别调整 --------------------------
+
+const defaultLevelWatchInterval = 2 * time.Second
+
+// WatchLevelFile 定期读取级别文件并应用其中的日志级别（如 "debug"），返回停止函数。
+// 文件不存在或被删除时恢复为配置中的级别，运维人员可以通过写入该文件临时提高正在运行任务的日志详细程度。
+func (l *Logger) WatchLevelFile(path string, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultLevelWatchInterval
+	}
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		lastContent := ""
+		for {
+			l.applyLevelFile(path, &lastContent)
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// applyLevelFile 读取级别文件，内容发生变化时应用新的日志级别
+func (l *Logger) applyLevelFile(path string, lastContent *string) {
+	data, err := os.ReadFile(path)
+	content := strings.TrimSpace(string(data))
+	if err != nil {
+		content = ""
+	}
+	if content == *lastContent {
+		return
+	}
+	*lastContent = content
+
+	if content == "" {
+		_ = l.SetLevel(l.configLevel().String())
+		return
+	}
+	if err := l.SetLevel(content); err != nil {
+		l.Warnf("ignore level file %s: %v", path, err)
+	}
+}
+
+// configLevel 返回配置中的日志级别
+func (l *Logger) configLevel() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return parseLevel(l.config.Level)
+}
+
+// toggleDebug 在 debug 与配置级别之间切换
+func (l *Logger) toggleDebug() {
+	if l.GetLevel() == zapcore.DebugLevel.String() {
+		_ = l.SetLevel(l.configLevel().String())
+	} else {
+		_ = l.SetLevel(zapcore.DebugLevel.String())
+	}
+	l.Infof("log level switched to %s", l.GetLevel())
+}
+
+// WatchLevelFile 为默认日志器监听级别文件
+func WatchLevelFile(path string, interval time.Duration) (stop func()) {
+	logger := getDefaultLogger()
+	if logger == nil {
+		return func() {}
+	}
+	return logger.WatchLevelFile(path, interval)
+}
+
+// ToggleDebugOnSignal 为默认日志器启用信号切换 debug 级别
+func ToggleDebugOnSignal() (stop func(), err error) {
+	logger := getDefaultLogger()
+	if logger == nil {
+		return func() {}, nil
+	}
+	return logger.ToggleDebugOnSignal()
+}
+
+// SetLevel 运行期修改默认日志器的级别
+func SetLevel(level string) error {
+	logger := getDefaultLogger()
+	if logger == nil {
+		return nil
+	}
+	return logger.SetLevel(level)
+}
+
+// GetLevel 获取默认日志器的当前级别
+func GetLevel() string {
+	logger := getDefaultLogger()
+	if logger == nil {
+		return ""
+	}
+	return logger.GetLevel()
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitLevel 等待日志器级别变为期望值
+func waitLevel(t *testing.T, logger *Logger, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if logger.GetLevel() == expected {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected level %s, got %s", expected, logger.GetLevel())
+}
+
+// TestSetLevel 测试运行期修改级别对子日志器同样生效
+func TestSetLevel(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "level.log")
+	logger, err := CreateLogger("level", NewLogConfig("info", logFile, "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+	child := logger.With(String("module", "child"))
+
+	child.Debugf("hidden debug")
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatalf("Failed to set level: %v", err)
+	}
+	if logger.GetLevel() != "debug" || child.GetLevel() != "debug" {
+		t.Fatalf("Unexpected level: %s / %s", logger.GetLevel(), child.GetLevel())
+	}
+	child.Debugf("visible debug")
+	_ = logger.Sync()
+
+	records := readJSONLogLines(t, logFile)
+	if len(records) != 1 || records[0]["msg"] != "visible debug" {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("Expected error for invalid level")
+	}
+}
+
+// TestWatchLevelFile 测试通过级别文件调整并恢复日志级别
+func TestWatchLevelFile(t *testing.T) {
+	levelFile := filepath.Join(t.TempDir(), "level")
+	logger, err := CreateLogger("level_file", NewLogConfig("warn", filepath.Join(t.TempDir(), "x.log"), "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	stop := logger.WatchLevelFile(levelFile, 10*time.Millisecond)
+	defer stop()
+
+	if err := os.WriteFile(levelFile, []byte("debug\n"), 0644); err != nil {
+		t.Fatalf("Failed to write level file: %v", err)
+	}
+	waitLevel(t, logger, "debug")
+
+	if err := os.Remove(levelFile); err != nil {
+		t.Fatalf("Failed to remove level file: %v", err)
+	}
+	waitLevel(t, logger, "warn")
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------- 采样与限流 --------------------------
+
+const (
+	defaultLimitInterval = time.Second
+	maxLimitKeys         = 10000 // 单个周期内记录的消息种类上限，超出后提前开始新周期，避免内存无限增长
+)
+
+// windowCounter 按固定周期统计每条消息出现的次数
+type windowCounter struct {
+	interval    time.Duration
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]uint64
+}
+
+func newWindowCounter(interval time.Duration) *windowCounter {
+	if interval <= 0 {
+		interval = defaultLimitInterval
+	}
+	return &windowCounter{interval: interval, counts: make(map[string]uint64)}
+}
+
+// incr 记录一次出现并返回本周期内的累计次数
+func (w *windowCounter) incr(key string, now time.Time) uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if now.Sub(w.windowStart) >= w.interval || len(w.counts) >= maxLimitKeys {
+		w.windowStart = now
+		w.counts = make(map[string]uint64)
+	}
+	w.counts[key]++
+	return w.counts[key]
+}
+
+// limitState 保存采样与限流的计数，派生的子日志器共享同一状态
+type limitState struct {
+	sampler          *windowCounter
+	sampleInitial    uint64
+	sampleThereafter uint64
+	rater            *windowCounter
+	rateLimit        uint64
+
+	sampledDropped atomic.Int64 // 自上次报告以来被采样丢弃的条数
+	rateDropped    atomic.Int64 // 自上次报告以来被限流丢弃的条数
+	totalDropped   atomic.Int64 // 累计丢弃条数
+
+	reportInterval time.Duration // 两次丢弃报告之间的最短间隔，取采样与限流周期中较短的一个
+	lastReport     atomic.Int64  // 上次报告丢弃条数的时间（UnixNano）
+}
+
+// newLimitState 根据配置创建采样与限流状态，未启用时返回 nil
+func newLimitState(config LogConfig) *limitState {
+	if config.SampleInitial <= 0 && config.RateLimit <= 0 {
+		return nil
+	}
+	state := &limitState{}
+	if config.SampleInitial > 0 {
+		state.sampler = newWindowCounter(config.SampleInterval)
+		state.sampleInitial = uint64(config.SampleInitial)
+		state.sampleThereafter = uint64(max(config.SampleThereafter, 0))
+		state.reportInterval = state.sampler.interval
+	}
+	if config.RateLimit > 0 {
+		state.rater = newWindowCounter(config.RateInterval)
+		state.rateLimit = uint64(config.RateLimit)
+		if state.reportInterval == 0 || state.rater.interval < state.reportInterval {
+			state.reportInterval = state.rater.interval
+		}
+	}
+	return state
+}
+
+// allow 判断日志是否应当输出，按级别与消息内容区分消息，Panic/Fatal 级别始终输出
+func (s *limitState) allow(entry zapcore.Entry) bool {
+	if entry.Level >= zapcore.DPanicLevel {
+		return true
+	}
+	key := entry.Level.String() + "|" + entry.Message
+
+	if s.sampler != nil {
+		n := s.sampler.incr(key, entry.Time)
+		if n > s.sampleInitial && (s.sampleThereafter == 0 || (n-s.sampleInitial)%s.sampleThereafter != 0) {
+			s.sampledDropped.Add(1)
+			s.totalDropped.Add(1)
+			return false
+		}
+	}
+	if s.rater != nil {
+		if s.rater.incr(key, entry.Time) > s.rateLimit {
+			s.rateDropped.Add(1)
+			s.totalDropped.Add(1)
+			return false
+		}
+	}
+	return true
+}
+
+// shouldReport 判断是否应在本条日志之前报告丢弃条数，距上次报告不足 reportInterval 时不报告，避免报告本身刷屏
+func (s *limitState) shouldReport(now time.Time) bool {
+	if s.sampledDropped.Load()+s.rateDropped.Load() == 0 {
+		return false
+	}
+	last := s.lastReport.Load()
+	if now.UnixNano()-last < int64(s.reportInterval) {
+		return false
+	}
+	return s.lastReport.CompareAndSwap(last, now.UnixNano())
+}
+
+// limitCore 在所有输出之前执行采样与限流
+type limitCore struct {
+	inner zapcore.Core
+	state *limitState
+}
+
+func newLimitCore(inner zapcore.Core, state *limitState) zapcore.Core {
+	if state == nil {
+		return inner
+	}
+	return &limitCore{inner: inner, state: state}
+}
+
+func (c *limitCore) Enabled(level zapcore.Level) bool {
+	return c.inner.Enabled(level)
+}
+
+func (c *limitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &limitCore{inner: c.inner.With(fields), state: c.state}
+}
+
+// Check 通过采样与限流判断后交给内部核心，由内部核心按各输出的级别筛选，
+// 有尚未报告的丢弃条数且距上次报告已满一个周期时先输出丢弃统计
+func (c *limitCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.inner.Enabled(entry.Level) || !c.state.allow(entry) {
+		return checked
+	}
+	if c.state.shouldReport(entry.Time) {
+		c.reportDropped()
+	}
+	return c.inner.Check(entry, checked)
+}
+
+// Write 交给内部核心，由内部核心按各输出的级别再次筛选
+func (c *limitCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if checked := c.inner.Check(entry, nil); checked != nil {
+		checked.Write(fields...)
+	}
+	return nil
+}
+
+// Sync 在刷新前输出尚未报告的丢弃条数
+func (c *limitCore) Sync() error {
+	c.reportDropped()
+	return c.inner.Sync()
+}
+
+// reportDropped 输出丢弃统计，报告本身不受采样与限流影响
+func (c *limitCore) reportDropped() {
+	c.state.lastReport.Store(time.Now().UnixNano())
+	sampled := c.state.sampledDropped.Swap(0)
+	rated := c.state.rateDropped.Swap(0)
+	if sampled+rated == 0 {
+		return
+	}
+	entry := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Now(),
+		Message: fmt.Sprintf("dropped %d log messages (sampled: %d, rate limited: %d)", sampled+rated, sampled, rated),
+	}
+	if checked := c.inner.Check(entry, nil); checked != nil {
+		checked.Write()
+	}
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// decodeJSONLines 解析缓冲区中的 JSON 日志行
+func decodeJSONLines(t *testing.T, data string) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to parse log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestSampling 测试同一消息先输出前 N 条，之后每 M 条输出一条，丢弃数量在下一条输出的日志之前报告，每个周期最多报告一次，其余在 Sync 时报告
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	config := NewLogConfig("info", "", "off")
+	config.Outputs = []OutputConfig{{Type: OutputWriter, Writer: &buf, Encoding: EncodingJSON}}
+	config.SampleInitial = 3
+	config.SampleThereafter = 10
+	config.SampleInterval = 0
+	logger, err := CreateLogger("sampling", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	for i := 0; i < 100; i++ {
+		logger.Warnw("skip row", "row", i)
+	}
+	if logger.DroppedCount() != 88 {
+		t.Errorf("Expected 88 dropped, got %d", logger.DroppedCount())
+	}
+	logger.Infof("other message")
+
+	// 前3条 + 丢弃报告 + 第13、23...93条(共9条) + 其他消息，同一周期内不再重复报告
+	records := decodeJSONLines(t, buf.String())
+	if len(records) != 14 {
+		t.Fatalf("Expected 14 records, got %d", len(records))
+	}
+	if records[3]["msg"] != "dropped 9 log messages (sampled: 9, rate limited: 0)" {
+		t.Errorf("Unexpected drop report: %v", records[3])
+	}
+	if records[4]["row"] != float64(12) {
+		t.Errorf("Unexpected sampled record: %v", records[4])
+	}
+
+	_ = logger.Sync()
+	records = decodeJSONLines(t, buf.String())
+	last := records[len(records)-1]
+	if last["msg"] != "dropped 79 log messages (sampled: 79, rate limited: 0)" {
+		t.Errorf("Unexpected drop report: %v", last)
+	}
+
+	// 报告后计数清零，再次 Sync 不会重复报告
+	_ = logger.Sync()
+	if len(decodeJSONLines(t, buf.String())) != len(records) {
+		t.Error("Expected no duplicate drop report")
+	}
+}
+
+// TestRateLimit 测试同一消息每个周期内最多输出的条数，内容不同的消息互不影响，且对子日志器同样生效
+func TestRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+	config := NewLogConfig("info", "", "off")
+	config.Outputs = []OutputConfig{{Type: OutputWriter, Writer: &buf, Encoding: EncodingJSON}}
+	config.RateLimit = 5
+	logger, err := CreateLogger("rate_limit", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	child := logger.With(String("module", "csv"))
+	for i := 0; i < 10; i++ {
+		child.Errorf("open failed %d", i)
+	}
+	for i := 0; i < 50; i++ {
+		child.Errorf("write failed")
+	}
+	if records := decodeJSONLines(t, buf.String()); len(records) != 15 {
+		t.Fatalf("Expected 15 records, got %d", len(records))
+	}
+	if logger.DroppedCount() != 45 {
+		t.Errorf("Expected 45 dropped, got %d", logger.DroppedCount())
+	}
+	_ = CloseAll()
+	if !strings.Contains(buf.String(), "rate limited: 45") {
+		t.Errorf("Expected drop report on CloseAll, got %q", buf.String())
+	}
+}
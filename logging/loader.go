@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// -------------------------- 配置文件加载 --------------------------
+
+// EnvPrefix 环境变量覆盖配置时使用的前缀
+const EnvPrefix = "XUTILS_LOG_"
+
+// LogConfigFile 多日志器配置文件结构，Loggers 中每个日志器以 Defaults 为基础覆盖各自的配置
+type LogConfigFile struct {
+	Defaults LogConfig            `yaml:"defaults" json:"defaults"`
+	Loggers  map[string]LogConfig `yaml:"loggers" json:"loggers"`
+}
+
+// envOverrides 支持的环境变量及其对应的配置项
+var envOverrides = []struct {
+	name  string
+	apply func(cfg *LogConfig, value string) error
+}{
+	{"LEVEL", func(cfg *LogConfig, v string) error { cfg.Level = v; return nil }},
+	{"FILE", func(cfg *LogConfig, v string) error { cfg.LogFile = v; return nil }},
+	{"CONSOLE_FORMAT", func(cfg *LogConfig, v string) error { cfg.ConsoleFormat = v; return nil }},
+	{"CONSOLE_LEVEL", func(cfg *LogConfig, v string) error { cfg.ConsoleLevel = v; return nil }},
+	{"CONSOLE_ENCODING", func(cfg *LogConfig, v string) error { cfg.ConsoleEncoding = v; return nil }},
+	{"CONSOLE_OUTPUT", func(cfg *LogConfig, v string) error { cfg.ConsoleOutput = v; return nil }},
+	{"CONSOLE_COLOR", func(cfg *LogConfig, v string) (err error) { cfg.ConsoleColor, err = strconv.ParseBool(v); return err }},
+	{"FILE_LEVEL", func(cfg *LogConfig, v string) error { cfg.FileLevel = v; return nil }},
+	{"FILE_ENCODING", func(cfg *LogConfig, v string) error { cfg.FileEncoding = v; return nil }},
+	{"RATE_LIMIT", func(cfg *LogConfig, v string) (err error) { cfg.RateLimit, err = strconv.Atoi(v); return err }},
+}
+
+// ApplyEnvOverrides 使用 XUTILS_LOG_ 开头的环境变量覆盖配置，如 XUTILS_LOG_LEVEL、XUTILS_LOG_FILE、XUTILS_LOG_CONSOLE_FORMAT，
+// 未设置的环境变量不影响原有配置
+func ApplyEnvOverrides(cfg *LogConfig) error {
+	for _, override := range envOverrides {
+		value, ok := os.LookupEnv(EnvPrefix + override.name)
+		if !ok {
+			continue
+		}
+		if err := override.apply(cfg, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("invalid env %s%s '%s': %w", EnvPrefix, override.name, value, err)
+		}
+	}
+	return nil
+}
+
+// LoadLogConfig 从 YAML 或 JSON 文件加载单个日志器配置，未配置的项使用 NewLogConfigEmpty 的默认值，
+// 文件中存在未知配置项时返回错误，加载后应用环境变量覆盖
+func LoadLogConfig(path string) (LogConfig, error) {
+	data, err := loadConfigData(path)
+	if err != nil {
+		return LogConfig{}, err
+	}
+
+	config := NewLogConfigEmpty()
+	if err := json.Unmarshal(data, &config); err != nil {
+		return LogConfig{}, fmt.Errorf("parse log config error: %w", err)
+	}
+	if unknown := utils.DetectUnknownFields(data, &config); len(unknown) > 0 {
+		return LogConfig{}, fmt.Errorf("unknown log config fields: %s", strings.Join(unknown, ", "))
+	}
+	if err := ApplyEnvOverrides(&config); err != nil {
+		return LogConfig{}, err
+	}
+	return config, nil
+}
+
+// LoadLogConfigs 从 YAML 或 JSON 文件加载多日志器配置，返回日志器名称到配置的映射。
+// 文件格式见 LogConfigFile，环境变量覆盖对每个日志器均生效
+func LoadLogConfigs(path string) (map[string]LogConfig, error) {
+	data, err := loadConfigData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Defaults json.RawMessage            `json:"defaults"`
+		Loggers  map[string]json.RawMessage `json:"loggers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse log config error: %w", err)
+	}
+
+	file := LogConfigFile{Defaults: NewLogConfigEmpty(), Loggers: make(map[string]LogConfig, len(raw.Loggers))}
+	if len(raw.Defaults) > 0 {
+		if err := json.Unmarshal(raw.Defaults, &file.Defaults); err != nil {
+			return nil, fmt.Errorf("parse log config defaults error: %w", err)
+		}
+	}
+	for name, content := range raw.Loggers {
+		config := file.Defaults
+		config.Outputs = append([]OutputConfig(nil), file.Defaults.Outputs...)
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("parse log config '%s' error: %w", name, err)
+		}
+		file.Loggers[name] = config
+	}
+	if unknown := utils.DetectUnknownFields(data, &file); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown log config fields: %s", strings.Join(unknown, ", "))
+	}
+	if len(file.Loggers) == 0 {
+		return nil, fmt.Errorf("no logger configured in %s", path)
+	}
+
+	for name, config := range file.Loggers {
+		if err := ApplyEnvOverrides(&config); err != nil {
+			return nil, err
+		}
+		file.Loggers[name] = config
+	}
+	return file.Loggers, nil
+}
+
+// CreateLoggersFromFile 加载多日志器配置文件并通过 CreateLogger 创建全部日志器，
+// 名为 "default" 的日志器同时作为包级函数使用的默认日志器。任意日志器创建失败时关闭已创建的日志器并返回错误
+func CreateLoggersFromFile(path string) (map[string]*Logger, error) {
+	configs, err := LoadLogConfigs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	loggers := make(map[string]*Logger, len(configs))
+	for name, config := range configs {
+		logger, err := CreateLogger(name, config)
+		if err != nil {
+			for created := range loggers {
+				_ = RemoveLogger(created)
+			}
+			return nil, fmt.Errorf("create log recorder '%s' error: %w", name, err)
+		}
+		loggers[name] = logger
+	}
+	if logger, ok := loggers["default"]; ok {
+		SetDefaultLogger(logger)
+	}
+	return loggers, nil
+}
+
+// loadConfigData 按扩展名读取 YAML 或 JSON 配置文件，统一转换为 JSON 数据
+func loadConfigData(path string) ([]byte, error) {
+	var content interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		if err := utils.LoadYAML(path, &content); err != nil {
+			return nil, err
+		}
+	case ".json":
+		if err := utils.LoadJSON(path, &content); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported log config file type: %s", path)
+	}
+	if content == nil {
+		content = map[string]interface{}{}
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("convert log config error: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON 解析日志配置，采样与限流周期支持 "1s"、"500ms" 形式的字符串或纳秒整数
+func (c *LogConfig) UnmarshalJSON(data []byte) error {
+	type plainConfig LogConfig
+	aux := struct {
+		*plainConfig
+		SampleInterval *jsonDuration `json:"sample_interval"`
+		RateInterval   *jsonDuration `json:"rate_interval"`
+	}{plainConfig: (*plainConfig)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.SampleInterval != nil {
+		c.SampleInterval = time.Duration(*aux.SampleInterval)
+	}
+	if aux.RateInterval != nil {
+		c.RateInterval = time.Duration(*aux.RateInterval)
+	}
+	return nil
+}
+
+// jsonDuration 支持字符串与整数两种形式的时间间隔
+type jsonDuration time.Duration
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		*d = jsonDuration(duration)
+		return nil
+	}
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err != nil {
+		return fmt.Errorf("invalid duration %s", string(data))
+	}
+	*d = jsonDuration(nanos)
+	return nil
+}
@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile 写入临时配置文件（测试辅助）
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write config file failed: %v", err)
+	}
+	return path
+}
+
+// TestLoadLogConfig 测试加载单个日志器配置及环境变量覆盖
+func TestLoadLogConfig(t *testing.T) {
+	path := writeConfigFile(t, "log.yml", `
+level: debug
+log_file: app.log
+console_format: "off"
+rate_limit: 5
+rate_interval: 2s
+outputs:
+  - type: ring
+    ring_size: 10
+`)
+	t.Setenv("XUTILS_LOG_LEVEL", "warn")
+
+	config, err := LoadLogConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Level != "warn" || config.LogFile != "app.log" || config.ConsoleFormat != "off" {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if config.RateLimit != 5 || config.RateInterval != 2*time.Second {
+		t.Errorf("Unexpected rate limit: %d %v", config.RateLimit, config.RateInterval)
+	}
+	if config.MaxSize != 100 || len(config.Outputs) != 1 || config.Outputs[0].RingSize != 10 {
+		t.Errorf("Expected defaults and outputs to be kept: %+v", config)
+	}
+
+	t.Setenv("XUTILS_LOG_CONSOLE_COLOR", "maybe")
+	if _, err := LoadLogConfig(path); err == nil {
+		t.Error("Expected error for invalid env value")
+	}
+}
+
+// TestLoadLogConfigUnknownFields 测试未知配置项
+func TestLoadLogConfigUnknownFields(t *testing.T) {
+	path := writeConfigFile(t, "log.json", `{"level":"info","lvl":"debug","outputs":[{"type":"ring","size":3}]}`)
+	_, err := LoadLogConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "lvl") || !strings.Contains(err.Error(), "outputs[*].size") {
+		t.Fatalf("Expected unknown fields error, got %v", err)
+	}
+}
+
+// TestCreateLoggersFromFile 测试通过配置文件一次创建多个日志器
+func TestCreateLoggersFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, "loggers.json", `{
+  "defaults": {"level": "debug", "console_format": "off"},
+  "loggers": {
+    "api": {"log_file": "`+filepath.ToSlash(filepath.Join(dir, "api.log"))+`"},
+    "job": {"level": "error", "outputs": [{"type": "ring", "ring_size": 5}]}
+  }
+}`)
+	loggers, err := CreateLoggersFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to create loggers: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	if len(loggers) != 2 {
+		t.Fatalf("Expected 2 loggers, got %d", len(loggers))
+	}
+	if logger, ok := GetLogger("api"); !ok || logger.GetLevel() != "debug" {
+		t.Error("Expected api logger with defaults level")
+	}
+	job := loggers["job"]
+	job.Warn("dropped")
+	job.Error("kept")
+	if lines := job.RingBuffer().Lines(); len(lines) != 1 {
+		t.Errorf("Expected 1 line, got %v", lines)
+	}
+
+	// 已存在同名日志器时返回错误
+	if _, err := CreateLoggersFromFile(path); err == nil {
+		t.Error("Expected error for existing loggers")
+	}
+}
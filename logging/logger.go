@@ -1,8 +1,9 @@
 package logging
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
 	"sync"
 
 	"go.uber.org/zap"
@@ -17,79 +18,253 @@ type Logger struct {
 	zapLogger *zap.Logger
 	sugar     *zap.SugaredLogger
 	config    LogConfig
+	level     zap.AtomicLevel // 运行期可调整的日志级别，派生的子日志器共享同一级别
+	holder    *coreHolder     // 当前输出核心，派生的子日志器共享，重新配置或关闭时整体替换
+	closers   []io.Closer     // 文件、syslog 等需要在关闭时释放的资源
+	ring      *RingBuffer     // 第一个环形缓冲区输出
+	limiter   *limitState     // 采样与限流状态，未启用时为 nil
+	derived   bool            // 是否为派生的子日志器，子日志器不持有输出资源
+	closed    bool
 	mu        sync.RWMutex
 }
 
+// builtCore 根据配置生成的输出核心及其附属资源
+type builtCore struct {
+	core    zapcore.Core
+	closers []io.Closer
+	ring    *RingBuffer
+	limiter *limitState
+}
+
 // init 初始化日志器核心(已修正EncodeTime配置)
 func (l *Logger) init() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	// 解析日志级别
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(l.config.Level)); err != nil {
-		level = zapcore.InfoLevel // 默认为info级别
+	l.level = zap.NewAtomicLevelAt(parseLevel(l.config.Level))
+
+	built, err := l.buildCore(l.config)
+	if err != nil {
+		return err
 	}
+	l.apply(built)
+
+	// 创建zap日志器
+	l.holder = newCoreHolder(built.core)
+	l.zapLogger = zap.New(
+		newSwapCore(l.holder),
+		zap.AddCaller(),      // 显示调用位置(如 main.go:20)
+		zap.AddCallerSkip(1), // 跳过内部方法，显示真实业务代码位置
+	)
+	l.sugar = l.zapLogger.Sugar()
 
-	// 准备输出核心
+	return nil
+}
+
+// NewLoggerWithCore 使用自定义输出核心创建日志器，不注册到日志器管理器，用于测试或接入其他输出。
+// core 应使用 level 判断级别，以便 SetLevel 生效
+func NewLoggerWithCore(core zapcore.Core, level zap.AtomicLevel) *Logger {
+	l := &Logger{
+		config: LogConfig{Level: level.Level().String()},
+		level:  level,
+		holder: newCoreHolder(core),
+	}
+	l.zapLogger = zap.New(newSwapCore(l.holder), zap.AddCaller(), zap.AddCallerSkip(1))
+	l.sugar = l.zapLogger.Sugar()
+	return l
+}
+
+// buildCore 根据配置准备输出核心，失败时释放已创建的资源
+func (l *Logger) buildCore(config LogConfig) (builtCore, error) {
+	specs, closers, err := sinkSpecs(config)
+	if err != nil {
+		_ = closeAll(closers)
+		return builtCore{}, err
+	}
+	built := builtCore{closers: closers}
 	var cores []zapcore.Core
+	for _, spec := range specs {
+		cores = append(cores, spec.newCore(l.level))
+		if spec.ring != nil && built.ring == nil {
+			built.ring = spec.ring
+		}
+	}
+	if len(cores) == 0 {
+		_ = closeAll(closers)
+		return builtCore{}, fmt.Errorf("no log output (console/file) has been configured")
+	}
+	built.limiter = newLimitState(config)
+	built.core = newLimitCore(zapcore.NewTee(cores...), built.limiter)
+	return built, nil
+}
+
+// apply 记录新输出核心的附属资源
+func (l *Logger) apply(built builtCore) {
+	l.closers = built.closers
+	l.ring = built.ring
+	l.limiter = built.limiter
+}
+
+// Reconfigure 使用新配置重建输出，日志器及其派生的子日志器立即切换到新输出，原有的文件等资源随后关闭。
+// 日志级别重置为新配置中的级别
+func (l *Logger) Reconfigure(config LogConfig) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == nil {
+		return fmt.Errorf("logger is not initialized")
+	}
+	if l.derived {
+		return fmt.Errorf("derived logger cannot be reconfigured")
+	}
+
+	built, err := l.buildCore(config)
+	if err != nil {
+		return err
+	}
+	oldCore := l.holder.load().core
+	oldClosers := l.closers
+
+	l.config = config
+	l.apply(built)
+	l.level.SetLevel(parseLevel(config.Level))
+	l.holder.store(built.core)
+	l.closed = false
+
+	_ = oldCore.Sync()
+	if err := closeAll(oldClosers); err != nil {
+		return fmt.Errorf("close previous log outputs error: %w", err)
+	}
+	return nil
+}
+
+// Close 刷新缓冲区并释放文件、syslog 等资源，关闭后该日志器及其派生的子日志器不再输出。
+// 派生的子日志器不持有资源，对其调用 Close 仅刷新缓冲区
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.zapLogger == nil || l.closed {
+		return nil
+	}
+	if l.derived {
+		return l.zapLogger.Sync()
+	}
+
+	var errList []error
+	if err := l.zapLogger.Sync(); err != nil {
+		errList = append(errList, err)
+	}
+	l.holder.store(zapcore.NewNopCore())
+	if err := closeAll(l.closers); err != nil {
+		errList = append(errList, err)
+	}
+	l.closers = nil
+	l.closed = true
+	return errors.Join(errList...)
+}
+
+// sinkSpecs 根据配置生成控制台、文件及额外输出目标，同时返回需要在关闭时释放的资源
+func sinkSpecs(config LogConfig) ([]sinkSpec, []io.Closer, error) {
+	var specs []sinkSpec
+	var closers []io.Closer
 
 	// 控制台输出
-	if l.config.ConsoleFormat != "" && l.config.ConsoleFormat != "off" {
-		encoder := newConsoleEncoder(l.config.ConsoleFormat)
-		cores = append(cores, zapcore.NewCore(
-			encoder,
-			zapcore.Lock(os.Stdout),
-			level,
-		))
-	}
-
-	// 文件输出(带日志轮转，正确配置时间格式)
-	if l.config.LogFile != "" {
-		if err := ensureDir(l.config.LogFile); err != nil {
-			return fmt.Errorf("failed to create log dir: %w", err)
+	if config.ConsoleFormat != "" && config.ConsoleFormat != "off" {
+		if err := validateEncoding(config.ConsoleEncoding); err != nil {
+			return nil, closers, err
+		}
+		out, err := consoleWriter(config.ConsoleOutput)
+		if err != nil {
+			return nil, closers, err
+		}
+		specs = append(specs, sinkSpec{
+			writer:     consoleSyncer(out),
+			level:      config.ConsoleLevel,
+			encoding:   config.ConsoleEncoding,
+			format:     config.ConsoleFormat,
+			timeLayout: config.ConsoleTimeLayout,
+			color:      config.ConsoleColor && isTerminal(out),
+		})
+	}
+
+	// 文件输出(带日志轮转)
+	if config.LogFile != "" {
+		if err := ensureDir(config.LogFile); err != nil {
+			return nil, closers, fmt.Errorf("failed to create log dir: %w", err)
+		}
+		if err := validateEncoding(config.FileEncoding); err != nil {
+			return nil, closers, err
 		}
 
 		// 日志轮转配置
 		rotator := &lumberjack.Logger{
-			Filename:   l.config.LogFile,
-			MaxSize:    l.config.MaxSize,    // 单个文件最大100MB
-			MaxBackups: l.config.MaxBackups, // 最多保留10个备份
-			MaxAge:     l.config.MaxAge,     // 保留30天
-			Compress:   l.config.Compress,   // 压缩备份文件
+			Filename:   config.LogFile,
+			MaxSize:    config.MaxSize,    // 单个文件最大100MB
+			MaxBackups: config.MaxBackups, // 最多保留10个备份
+			MaxAge:     config.MaxAge,     // 保留30天
+			Compress:   config.Compress,   // 压缩备份文件
 		}
 
-		// 配置文件日志编码器(含时间格式)
-		fileEncoderCfg := zap.NewProductionEncoderConfig()
-		// 配置时间格式为ISO8601(如：2024-05-20T15:30:00.000Z)
-		fileEncoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
-		// (可选)自定义时间格式示例(如：2024-05-20 15:30:00.000)
-		// fileEncoderCfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		// 	enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
-		// }
+		encoding := config.FileEncoding
+		if encoding == "" {
+			encoding = EncodingJSON
+		}
+		specs = append(specs, sinkSpec{
+			writer:     zapcore.AddSync(rotator),
+			level:      config.FileLevel,
+			encoding:   encoding,
+			format:     "TLCM",
+			timeLayout: config.FileTimeLayout,
+		})
+		closers = append(closers, rotator)
+	}
 
-		// 创建JSON格式编码器
-		fileEncoder := zapcore.NewJSONEncoder(fileEncoderCfg)
-		cores = append(cores, zapcore.NewCore(
-			fileEncoder,
-			zapcore.AddSync(rotator),
-			level,
-		))
+	// 额外输出目标
+	for i, output := range config.Outputs {
+		spec, closer, err := output.outputSpec()
+		if err != nil {
+			return nil, closers, fmt.Errorf("invalid log output #%d: %w", i, err)
+		}
+		specs = append(specs, spec)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
 	}
+	return specs, closers, nil
+}
 
-	if len(cores) == 0 {
-		return fmt.Errorf("no log output (console/file) has been configured")
+// DroppedCount 获取因采样或限流累计丢弃的日志条数
+func (l *Logger) DroppedCount() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.limiter == nil {
+		return 0
 	}
+	return l.limiter.totalDropped.Load()
+}
 
-	// 创建zap日志器
-	l.zapLogger = zap.New(
-		zapcore.NewTee(cores...),
-		zap.AddCaller(),      // 显示调用位置(如 main.go:20)
-		zap.AddCallerSkip(2), // 跳过内部方法，显示真实业务代码位置
-	)
-	l.sugar = l.zapLogger.Sugar()
+// RingBuffer 获取日志器的第一个环形缓冲区输出，未配置时返回 nil
+func (l *Logger) RingBuffer() *RingBuffer {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.ring
+}
 
-	return nil
+// derive 基于当前日志器派生新的日志器，派生日志器与父日志器共享输出
+func (l *Logger) derive(opts ...zap.Option) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	child := &Logger{config: l.config, level: l.level, holder: l.holder, ring: l.ring, limiter: l.limiter, derived: true}
+	if l.zapLogger != nil {
+		child.zapLogger = l.zapLogger.WithOptions(opts...)
+		child.sugar = child.zapLogger.Sugar()
+	}
+	return child
+}
+
+// With 派生携带固定字段的子日志器，如目标、任务ID、模块名等，子日志器的每条日志都会附带这些字段
+func (l *Logger) With(fields ...Field) *Logger {
+	return l.derive(zap.Fields(fields...))
 }
 
 // 日志输出方法
@@ -173,6 +348,113 @@ func (l *Logger) Fatal(args ...interface{}) {
 	}
 }
 
+// SetLevel 运行期修改日志级别，立即对该日志器及其派生的子日志器生效
+func (l *Logger) SetLevel(level string) error {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level '%s': %w", level, err)
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger == nil {
+		return fmt.Errorf("logger is not initialized")
+	}
+	l.level.SetLevel(lvl)
+	return nil
+}
+
+// GetLevel 获取当前日志级别
+func (l *Logger) GetLevel() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger == nil {
+		return ""
+	}
+	return l.level.Level().String()
+}
+
+// 结构化日志输出方法，keysAndValues 为交替出现的键和值
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.sugar != nil {
+		l.sugar.Debugw(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.sugar != nil {
+		l.sugar.Infow(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.sugar != nil {
+		l.sugar.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.sugar != nil {
+		l.sugar.Errorw(msg, keysAndValues...)
+	}
+}
+
+func (l *Logger) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.sugar != nil {
+		l.sugar.Fatalw(msg, keysAndValues...)
+	}
+}
+
+// 强类型字段日志输出方法
+func (l *Logger) DebugFields(msg string, fields ...Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger != nil {
+		l.zapLogger.Debug(msg, fields...)
+	}
+}
+
+func (l *Logger) InfoFields(msg string, fields ...Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger != nil {
+		l.zapLogger.Info(msg, fields...)
+	}
+}
+
+func (l *Logger) WarnFields(msg string, fields ...Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger != nil {
+		l.zapLogger.Warn(msg, fields...)
+	}
+}
+
+func (l *Logger) ErrorFields(msg string, fields ...Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger != nil {
+		l.zapLogger.Error(msg, fields...)
+	}
+}
+
+func (l *Logger) FatalFields(msg string, fields ...Field) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.zapLogger != nil {
+		l.zapLogger.Fatal(msg, fields...)
+	}
+}
+
 // Sync 刷新日志缓冲区
 func (l *Logger) Sync() error {
 	l.mu.RLock()
@@ -1,8 +1,11 @@
 package logging
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -78,3 +81,55 @@ func TestLoggerConcurrent(t *testing.T) {
 		<-done
 	}
 }
+
+// readJSONLogLines 读取 JSON 格式日志文件的全部记录
+func readJSONLogLines(t *testing.T, logFile string) []map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to parse log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestLoggerStructured 测试结构化字段写入文件后可以按键查询
+func TestLoggerStructured(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "structured.log")
+	logger, err := CreateLogger("structured", NewLogConfig("debug", logFile, "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	logger.Infow("scan finished", "target", "example.com", "count", 3)
+	child := logger.With(String("task_id", "t-1"), String("module", "scanner"))
+	child.WarnFields("request failed", Int("status", 502), Err(errors.New("bad gateway")))
+	_ = logger.Sync()
+
+	records := readJSONLogLines(t, logFile)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0]["msg"] != "scan finished" || records[0]["target"] != "example.com" || records[0]["count"] != float64(3) {
+		t.Errorf("Unexpected infow record: %v", records[0])
+	}
+	if records[1]["task_id"] != "t-1" || records[1]["module"] != "scanner" || records[1]["status"] != float64(502) || records[1]["error"] != "bad gateway" {
+		t.Errorf("Unexpected child record: %v", records[1])
+	}
+	if caller, _ := records[1]["caller"].(string); !strings.HasPrefix(caller, "logging/logger_test.go") {
+		t.Errorf("Expected caller in logger_test.go, got %q", caller)
+	}
+}
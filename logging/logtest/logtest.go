@@ -0,0 +1,127 @@
+// Package logtest 提供基于内存观察核心的日志器，用于在测试中断言日志输出。
+package logtest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/winezer0/xutils/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Entry 一条被记录的日志，包含级别、消息、调用位置及附带字段，ContextMap 可获取字段映射
+type Entry = observer.LoggedEntry
+
+// Logger 将日志记录在内存中的日志器，可直接当作 *logging.Logger 使用
+type Logger struct {
+	*logging.Logger
+	logs *observer.ObservedLogs
+}
+
+// New 创建记录日志的日志器，level 为空或无效时记录全部级别
+func New(level string) *Logger {
+	lvl := zapcore.DebugLevel
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			lvl = zapcore.DebugLevel
+		}
+	}
+	atomicLevel := zap.NewAtomicLevelAt(lvl)
+	core, logs := observer.New(atomicLevel)
+	return &Logger{
+		Logger: logging.NewLoggerWithCore(core, atomicLevel),
+		logs:   logs,
+	}
+}
+
+// NewDefault 创建记录全部级别的日志器并在测试期间替换包级默认日志器，测试结束后自动恢复
+func NewDefault(t testing.TB) *Logger {
+	t.Helper()
+	logger := New("")
+	SwapDefault(t, logger.Logger)
+	return logger
+}
+
+// SwapDefault 在测试期间将包级默认日志器替换为 logger，测试结束后恢复原默认日志器
+func SwapDefault(t testing.TB, logger *logging.Logger) {
+	t.Helper()
+	previous := logging.SetDefaultLogger(logger)
+	t.Cleanup(func() {
+		logging.SetDefaultLogger(previous)
+	})
+}
+
+// Entries 获取已记录的全部日志
+func (l *Logger) Entries() []Entry {
+	return l.logs.All()
+}
+
+// Len 获取已记录的日志条数
+func (l *Logger) Len() int {
+	return l.logs.Len()
+}
+
+// Reset 清空已记录的日志
+func (l *Logger) Reset() {
+	l.logs.TakeAll()
+}
+
+// FilterLevel 获取指定级别的日志，级别无效时返回空
+func (l *Logger) FilterLevel(level string) []Entry {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil
+	}
+	return l.logs.FilterLevelExact(lvl).All()
+}
+
+// FilterMessage 获取消息包含 substr 的日志
+func (l *Logger) FilterMessage(substr string) []Entry {
+	return l.logs.FilterMessageSnippet(substr).All()
+}
+
+// FilterField 获取附带指定字段且字段值相等的日志，整数字段的值为 int64
+func (l *Logger) FilterField(key string, value interface{}) []Entry {
+	return l.logs.Filter(func(entry Entry) bool {
+		actual, ok := entry.ContextMap()[key]
+		return ok && reflect.DeepEqual(actual, value)
+	}).All()
+}
+
+// AssertLogged 断言存在指定级别且消息包含 substr 的日志，不存在时报告测试失败并列出已记录的日志
+func (l *Logger) AssertLogged(t testing.TB, level, substr string) {
+	t.Helper()
+	for _, entry := range l.FilterLevel(level) {
+		if strings.Contains(entry.Message, substr) {
+			return
+		}
+	}
+	t.Errorf("expected %s log containing %q, got:\n%s", level, substr, l.dump())
+}
+
+// AssertNotLogged 断言不存在消息包含 substr 的日志
+func (l *Logger) AssertNotLogged(t testing.TB, substr string) {
+	t.Helper()
+	if entries := l.FilterMessage(substr); len(entries) > 0 {
+		t.Errorf("unexpected log containing %q, got:\n%s", substr, l.dump())
+	}
+}
+
+// dump 将已记录的日志格式化为便于阅读的文本
+func (l *Logger) dump() string {
+	var b strings.Builder
+	for _, entry := range l.Entries() {
+		b.WriteString("  ")
+		b.WriteString(entry.Level.CapitalString())
+		b.WriteString(" ")
+		b.WriteString(entry.Message)
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return "  (no logs)\n"
+	}
+	return b.String()
+}
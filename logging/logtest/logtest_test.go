@@ -0,0 +1,62 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/winezer0/xutils/logging"
+)
+
+// TestLogger 测试记录日志及过滤
+func TestLogger(t *testing.T) {
+	logger := New("info")
+	logger.Debugf("ignored")
+	logger.Infof("scan %s", "a.com")
+	logger.With(logging.String("target", "b.com")).Warnw("slow", "cost", 3)
+
+	if logger.Len() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", logger.Len())
+	}
+	if entries := logger.FilterLevel("warn"); len(entries) != 1 || entries[0].Message != "slow" {
+		t.Errorf("Unexpected warn entries: %v", entries)
+	}
+	if entries := logger.FilterMessage("a.com"); len(entries) != 1 {
+		t.Errorf("Unexpected message entries: %v", entries)
+	}
+	if entries := logger.FilterField("cost", int64(3)); len(entries) != 1 || entries[0].ContextMap()["target"] != "b.com" {
+		t.Errorf("Unexpected field entries: %v", entries)
+	}
+	logger.AssertLogged(t, "info", "scan a.com")
+	logger.AssertNotLogged(t, "ignored")
+
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatalf("Failed to set level: %v", err)
+	}
+	logger.Debugf("visible")
+	logger.AssertLogged(t, "debug", "visible")
+
+	logger.Reset()
+	if len(logger.Entries()) != 0 {
+		t.Error("Expected entries to be cleared")
+	}
+}
+
+// TestSwapDefault 测试替换并恢复默认日志器
+func TestSwapDefault(t *testing.T) {
+	outer := New("")
+	SwapDefault(t, outer.Logger)
+
+	t.Run("inner", func(t *testing.T) {
+		inner := NewDefault(t)
+		logging.Infof("inner message")
+		logging.DefaultLibLogger().Warnf("library message")
+		inner.AssertLogged(t, "info", "inner message")
+		inner.AssertLogged(t, "warn", "library message")
+	})
+
+	logging.Infof("outer message")
+	outer.AssertLogged(t, "info", "outer message")
+	outer.AssertNotLogged(t, "inner message")
+	if entry := outer.Entries()[0]; entry.Caller.File == "" {
+		t.Error("Expected caller to be recorded")
+	}
+}
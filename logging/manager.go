@@ -21,21 +21,43 @@ func (manager *loggerManager) GetLogger(name string) (*Logger, bool) {
 	return logger, exists
 }
 
-// CloseAll 关闭所有日志器
+// RemoveLogger 移除并关闭指定日志器
+func (manager *loggerManager) RemoveLogger(name string) error {
+	manager.mu.Lock()
+	logger, exists := manager.loggers[name]
+	delete(manager.loggers, name)
+	manager.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("log recorder not exist: %s", name)
+	}
+	return closeLogger(name, logger)
+}
+
+// CloseAll 关闭所有日志器并释放文件句柄
 func (manager *loggerManager) CloseAll() error {
 	manager.mu.Lock()
-	defer manager.mu.Unlock()
+	loggers := manager.loggers
+	manager.loggers = make(map[string]*Logger)
+	manager.mu.Unlock()
 
 	var errList []error
-	for name, logger := range manager.loggers {
-		if err := logger.Sync(); err != nil {
-			errList = append(errList, fmt.Errorf("close log recorder '%s' error: %w", name, err))
+	for name, logger := range loggers {
+		if err := closeLogger(name, logger); err != nil {
+			errList = append(errList, err)
 		}
 	}
-	manager.loggers = make(map[string]*Logger)
 	return errors.Join(errList...)
 }
 
+// closeLogger 关闭日志器，关闭的是默认日志器时一并重置，包级函数下次调用时重新初始化
+func closeLogger(name string, logger *Logger) error {
+	resetDefaultLogger(logger)
+	if err := logger.Close(); err != nil {
+		return fmt.Errorf("close log recorder '%s' error: %w", name, err)
+	}
+	return nil
+}
+
 var (
 	globalManager *loggerManager
 	once          sync.Once
@@ -62,15 +84,82 @@ func (manager *loggerManager) CreateLogger(name string, config LogConfig) (*Logg
 		return nil, fmt.Errorf("log recorder already exist: %s", name)
 	}
 
-	logger := &Logger{config: config}
-	if err := logger.init(); err != nil {
+	logger, err := newLogger(config)
+	if err != nil {
+		return nil, err
+	}
+
+	manager.mu.Lock()
+	if _, exists := manager.loggers[name]; exists {
+		manager.mu.Unlock()
+		_ = logger.Close()
+		return nil, fmt.Errorf("log recorder already exist: %s", name)
+	}
+	manager.loggers[name] = logger
+	manager.mu.Unlock()
+
+	return logger, nil
+}
+
+// GetOrCreateLogger 获取已创建的日志器，不存在时使用 config 创建，已存在时忽略 config
+func (manager *loggerManager) GetOrCreateLogger(name string, config LogConfig) (*Logger, error) {
+	if name == "" {
+		return nil, fmt.Errorf("log recorder name cannot be empty")
+	}
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if logger, exists := manager.loggers[name]; exists {
+		return logger, nil
+	}
+
+	logger, err := newLogger(config)
+	if err != nil {
+		return nil, err
+	}
+	manager.loggers[name] = logger
+	return logger, nil
+}
+
+// ReplaceLogger 使用新配置创建日志器替换同名日志器并关闭旧日志器，不存在时直接创建。
+// 旧日志器及其派生的子日志器关闭后不再输出，需要已持有的引用继续可用时使用 Reconfigure
+func (manager *loggerManager) ReplaceLogger(name string, config LogConfig) (*Logger, error) {
+	if name == "" {
+		return nil, fmt.Errorf("log recorder name cannot be empty")
+	}
+	logger, err := newLogger(config)
+	if err != nil {
 		return nil, err
 	}
 
 	manager.mu.Lock()
+	old, exists := manager.loggers[name]
 	manager.loggers[name] = logger
 	manager.mu.Unlock()
 
+	if exists {
+		replaceDefaultLogger(old, logger)
+		if err := old.Close(); err != nil {
+			return logger, fmt.Errorf("close log recorder '%s' error: %w", name, err)
+		}
+	}
+	return logger, nil
+}
+
+// Reconfigure 使用新配置原地重建指定日志器的输出，已持有的日志器及其子日志器引用继续有效
+func (manager *loggerManager) Reconfigure(name string, config LogConfig) error {
+	logger, exists := manager.GetLogger(name)
+	if !exists {
+		return fmt.Errorf("log recorder not exist: %s", name)
+	}
+	return logger.Reconfigure(config)
+}
+
+// newLogger 创建并初始化日志器
+func newLogger(config LogConfig) (*Logger, error) {
+	logger := &Logger{config: config}
+	if err := logger.init(); err != nil {
+		return nil, err
+	}
 	return logger, nil
 }
 
@@ -86,8 +175,32 @@ func GetLogger(name string) (*Logger, bool) {
 	return manager.GetLogger(name)
 }
 
-// CloseAll 关闭所有日志器
+// CloseAll 关闭所有日志器并释放文件句柄
 func CloseAll() error {
 	manager := createManagerOnce()
 	return manager.CloseAll()
 }
+
+// RemoveLogger 移除并关闭指定日志器
+func RemoveLogger(name string) error {
+	manager := createManagerOnce()
+	return manager.RemoveLogger(name)
+}
+
+// GetOrCreateLogger 获取已创建的日志器，不存在时使用 config 创建
+func GetOrCreateLogger(name string, config LogConfig) (*Logger, error) {
+	manager := createManagerOnce()
+	return manager.GetOrCreateLogger(name, config)
+}
+
+// ReplaceLogger 使用新配置创建日志器替换同名日志器并关闭旧日志器
+func ReplaceLogger(name string, config LogConfig) (*Logger, error) {
+	manager := createManagerOnce()
+	return manager.ReplaceLogger(name, config)
+}
+
+// Reconfigure 使用新配置原地重建指定日志器的输出
+func Reconfigure(name string, config LogConfig) error {
+	manager := createManagerOnce()
+	return manager.Reconfigure(name, config)
+}
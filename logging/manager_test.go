@@ -76,3 +76,106 @@ func TestGetLogger(t *testing.T) {
 		t.Error("Expected logger to not exist")
 	}
 }
+
+// TestRemoveLogger 测试移除日志器后释放文件且不再输出
+func TestRemoveLogger(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "remove.log")
+	logger, err := CreateLogger("remove", NewLogConfig("info", logFile, "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	child := logger.With(String("job", "1"))
+	logger.Info("before remove")
+
+	if err := RemoveLogger("remove"); err != nil {
+		t.Fatalf("Failed to remove logger: %v", err)
+	}
+	if _, exists := GetLogger("remove"); exists {
+		t.Error("Expected logger to be removed")
+	}
+	if err := RemoveLogger("remove"); err == nil {
+		t.Error("Expected error when removing missing logger")
+	}
+
+	logger.Info("after remove")
+	child.Info("child after remove")
+	if records := readJSONLogLines(t, logFile); len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	// 移除后可以重新创建同名日志器
+	if _, err := CreateLogger("remove", NewLogConfig("info", logFile, "off")); err != nil {
+		t.Fatalf("Failed to recreate logger: %v", err)
+	}
+	_ = CloseAll()
+}
+
+// TestReconfigure 测试原地重新配置日志器，已派生的子日志器切换到新输出
+func TestReconfigure(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.log")
+	newFile := filepath.Join(dir, "new.log")
+	logger, err := CreateLogger("reconfigure", NewLogConfig("info", oldFile, "off"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+	child := logger.With(String("job", "1"))
+	child.Info("old output")
+
+	if err := Reconfigure("reconfigure", NewLogConfig("debug", newFile, "off")); err != nil {
+		t.Fatalf("Failed to reconfigure logger: %v", err)
+	}
+	child.Debug("new output")
+	_ = logger.Sync()
+
+	if records := readJSONLogLines(t, oldFile); len(records) != 1 {
+		t.Errorf("Expected 1 record in old file, got %d", len(records))
+	}
+	records := readJSONLogLines(t, newFile)
+	if len(records) != 1 || records[0]["job"] != "1" || records[0]["level"] != "debug" {
+		t.Errorf("Unexpected records in new file: %v", records)
+	}
+	if err := Reconfigure("missing", NewLogConfigEmpty()); err == nil {
+		t.Error("Expected error when reconfiguring missing logger")
+	}
+}
+
+// TestGetOrCreateAndReplaceLogger 测试获取或创建以及替换日志器
+func TestGetOrCreateAndReplaceLogger(t *testing.T) {
+	defer func() {
+		_ = CloseAll()
+	}()
+	config := NewLogConfig("info", "", "off")
+	config.Outputs = []OutputConfig{{Type: OutputRing, RingSize: 10}}
+
+	first, err := GetOrCreateLogger("job", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	second, err := GetOrCreateLogger("job", NewLogConfigEmpty())
+	if err != nil || second != first {
+		t.Fatalf("Expected existing logger, got %v, %v", second, err)
+	}
+
+	replaced, err := ReplaceLogger("job", config)
+	if err != nil {
+		t.Fatalf("Failed to replace logger: %v", err)
+	}
+	if replaced == first {
+		t.Fatal("Expected a new logger")
+	}
+	if current, _ := GetLogger("job"); current != replaced {
+		t.Error("Expected registered logger to be replaced")
+	}
+	first.Info("closed")
+	replaced.Info("open")
+	if lines := first.RingBuffer().Lines(); len(lines) != 0 {
+		t.Errorf("Expected closed logger to drop output, got %v", lines)
+	}
+	if lines := replaced.RingBuffer().Lines(); len(lines) != 1 {
+		t.Errorf("Expected 1 line, got %v", lines)
+	}
+}
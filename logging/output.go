@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// -------------------------- 额外输出目标 --------------------------
+
+const (
+	OutputStdout = "stdout" // 标准输出
+	OutputStderr = "stderr" // 标准错误
+	OutputFile   = "file"   // 带轮转的日志文件
+	OutputSyslog = "syslog" // 通过本地 UDP/unix 套接字发送到 syslog
+	OutputWriter = "writer" // 任意 io.Writer
+	OutputRing   = "ring"   // 内存环形缓冲区，保留最近 N 行日志
+)
+
+// OutputConfig 单个输出目标的配置，每个输出拥有独立的级别与编码
+type OutputConfig struct {
+	Type       string `yaml:"type" json:"type"`               // 输出类型: stdout/stderr/file/syslog/writer/ring
+	Level      string `yaml:"level" json:"level"`             // 输出级别，与 LogConfig.Level 同时生效，空串表示只按 LogConfig.Level 过滤
+	Encoding   string `yaml:"encoding" json:"encoding"`       // 编码: "console"或"json"，空串时文件与syslog默认json，其余默认console
+	Format     string `yaml:"format" json:"format"`           // 文本编码启用的键，支持"T(时间)L(级别)C(调用者)M(消息)"，空串表示"TLCM"
+	TimeLayout string `yaml:"time_layout" json:"time_layout"` // 时间格式，空串表示ISO8601
+	Color      bool   `yaml:"color" json:"color"`             // 输出为终端时以彩色输出日志级别，仅对 stdout/stderr 生效
+
+	Path       string `yaml:"path" json:"path"`               // file: 日志文件路径
+	MaxSize    int    `yaml:"max_size" json:"max_size"`       // file: 单个日志文件最大大小(MB)
+	MaxBackups int    `yaml:"max_backups" json:"max_backups"` // file: 最多保留几个日志文件备份
+	MaxAge     int    `yaml:"max_age" json:"max_age"`         // file: 日志文件保留多少天
+	Compress   bool   `yaml:"compress" json:"compress"`       // file: 日志备份文件是否压缩
+
+	Network  string `yaml:"network" json:"network"`   // syslog: 网络类型 udp/unixgram/unix，空串表示 udp
+	Address  string `yaml:"address" json:"address"`   // syslog: 地址，空串表示 127.0.0.1:514
+	Tag      string `yaml:"tag" json:"tag"`           // syslog: 消息标签，空串表示进程名
+	Facility int    `yaml:"facility" json:"facility"` // syslog: 设施编号，0 表示 user(1)
+
+	Writer   io.Writer   `yaml:"-" json:"-"`                 // writer: 输出目标
+	Ring     *RingBuffer `yaml:"-" json:"-"`                 // ring: 外部创建的环形缓冲区，为空时按 RingSize 创建
+	RingSize int         `yaml:"ring_size" json:"ring_size"` // ring: 环形缓冲区保留的行数
+}
+
+// outputSpec 根据输出配置生成输出目标描述，返回需要在关闭日志器时释放的资源
+func (cfg OutputConfig) outputSpec() (sinkSpec, io.Closer, error) {
+	if err := validateEncoding(cfg.Encoding); err != nil {
+		return sinkSpec{}, nil, err
+	}
+	spec := sinkSpec{
+		level:      cfg.Level,
+		encoding:   strings.ToLower(cfg.Encoding),
+		format:     cfg.Format,
+		timeLayout: cfg.TimeLayout,
+	}
+	if spec.format == "" {
+		spec.format = "TLCM"
+	}
+	if spec.encoding == "" {
+		spec.encoding = EncodingConsole
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case OutputStdout, OutputStderr:
+		out, err := consoleWriter(cfg.Type)
+		if err != nil {
+			return sinkSpec{}, nil, err
+		}
+		spec.writer = consoleSyncer(out)
+		spec.color = cfg.Color && isTerminal(out)
+		return spec, nil, nil
+	case OutputFile:
+		if cfg.Path == "" {
+			return sinkSpec{}, nil, fmt.Errorf("file output requires path")
+		}
+		if err := ensureDir(cfg.Path); err != nil {
+			return sinkSpec{}, nil, fmt.Errorf("failed to create log dir: %w", err)
+		}
+		rotator := &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		}
+		if cfg.Encoding == "" {
+			spec.encoding = EncodingJSON
+		}
+		spec.writer = zapcore.AddSync(rotator)
+		return spec, rotator, nil
+	case OutputSyslog:
+		writer, err := newSyslogWriter(cfg.Network, cfg.Address, cfg.Tag, cfg.Facility)
+		if err != nil {
+			return sinkSpec{}, nil, err
+		}
+		if cfg.Encoding == "" {
+			spec.encoding = EncodingJSON
+		}
+		spec.syslog = writer
+		return spec, writer, nil
+	case OutputWriter:
+		if cfg.Writer == nil {
+			return sinkSpec{}, nil, fmt.Errorf("writer output requires writer")
+		}
+		spec.writer = zapcore.Lock(zapcore.AddSync(cfg.Writer))
+		return spec, nil, nil
+	case OutputRing:
+		ring := cfg.Ring
+		if ring == nil {
+			if cfg.RingSize <= 0 {
+				return sinkSpec{}, nil, fmt.Errorf("ring output requires ring or ring size")
+			}
+			ring = NewRingBuffer(cfg.RingSize)
+		}
+		spec.writer = ring
+		spec.ring = ring
+		return spec, nil, nil
+	default:
+		return sinkSpec{}, nil, fmt.Errorf("unsupported log output type: %s", cfg.Type)
+	}
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOutputsWriterAndRing 测试自定义 Writer 与环形缓冲区输出各自使用独立的级别和编码
+func TestOutputsWriterAndRing(t *testing.T) {
+	var buf bytes.Buffer
+	config := NewLogConfig("debug", "", "off")
+	config.Outputs = []OutputConfig{
+		{Type: OutputWriter, Writer: &buf, Level: "warn", Encoding: EncodingJSON},
+		{Type: OutputRing, RingSize: 3, Format: "LM"},
+	}
+	logger, err := CreateLogger("outputs", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	for i := 0; i < 5; i++ {
+		logger.Debugf("debug %d", i)
+	}
+	logger.Warnf("warn message")
+
+	record := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to parse writer output %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "warn message" || record["level"] != "warn" {
+		t.Errorf("Unexpected writer record: %v", record)
+	}
+
+	ring := logger.RingBuffer()
+	if ring == nil {
+		t.Fatal("Expected ring buffer")
+	}
+	expected := []string{"DEBUG\tdebug 3", "DEBUG\tdebug 4", "WARN\twarn message"}
+	if lines := ring.Lines(); strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unexpected ring lines: %q", lines)
+	}
+}
+
+// TestOutputsExtraFile 测试额外文件输出
+func TestOutputsExtraFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.log")
+	errorFile := filepath.Join(tmpDir, "error.log")
+	config := NewLogConfig("info", mainFile, "off")
+	config.Outputs = []OutputConfig{{Type: OutputFile, Path: errorFile, Level: "error"}}
+	logger, err := CreateLogger("extra_file", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	logger.Infof("info message")
+	logger.Errorf("error message")
+	_ = logger.Sync()
+
+	if records := readJSONLogLines(t, mainFile); len(records) != 2 {
+		t.Errorf("Expected 2 main records, got %d", len(records))
+	}
+	records := readJSONLogLines(t, errorFile)
+	if len(records) != 1 || records[0]["msg"] != "error message" {
+		t.Errorf("Unexpected error file records: %v", records)
+	}
+}
+
+// TestOutputsSyslog 测试通过 UDP 发送 syslog 消息
+func TestOutputsSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp listener unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	config := NewLogConfig("info", "", "off")
+	config.Outputs = []OutputConfig{{Type: OutputSyslog, Address: conn.LocalAddr().String(), Tag: "xutils", Facility: 16}}
+	logger, err := CreateLogger("syslog", config)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() {
+		_ = CloseAll()
+	}()
+
+	logger.With(String("target", "a.com")).Warnf("syslog message")
+
+	packet := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(packet)
+	if err != nil {
+		t.Fatalf("Failed to read syslog packet: %v", err)
+	}
+	message := string(packet[:n])
+	// local0(16)*8 + warning(4) = 132
+	if !strings.HasPrefix(message, "<132>") || !strings.Contains(message, "xutils[") || !strings.Contains(message, `"target":"a.com"`) {
+		t.Errorf("Unexpected syslog message: %q", message)
+	}
+}
+
+// TestOutputsInvalid 测试无效的输出配置
+func TestOutputsInvalid(t *testing.T) {
+	for i, output := range []OutputConfig{
+		{Type: "kafka"},
+		{Type: OutputFile},
+		{Type: OutputWriter},
+		{Type: OutputRing},
+	} {
+		config := NewLogConfig("info", "", "off")
+		config.Outputs = []OutputConfig{output}
+		if _, err := CreateLogger("invalid_outputs", config); err == nil {
+			t.Errorf("Expected error for output #%d: %+v", i, output)
+		}
+	}
+}
+
+// TestRingBufferWrap 测试环形缓冲区覆盖最旧的日志
+func TestRingBufferWrap(t *testing.T) {
+	ring := NewRingBuffer(2)
+	if ring.String() != "" {
+		t.Errorf("Expected empty ring")
+	}
+	_, _ = ring.Write([]byte("a\n"))
+	_, _ = ring.Write([]byte("b\n"))
+	_, _ = ring.Write([]byte("c\n"))
+	if ring.String() != "b\nc\n" {
+		t.Errorf("Unexpected ring content: %q", ring.String())
+	}
+	ring.Reset()
+	if len(ring.Lines()) != 0 {
+		t.Errorf("Expected empty ring after reset")
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// RingBuffer 内存环形缓冲区，保留最近写入的 N 行日志，可用于在崩溃报告中输出最后的日志
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewRingBuffer 创建保留 size 行日志的环形缓冲区
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{lines: make([]string, size)}
+}
+
+// Write 写入一条日志，每次写入视为一行
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// Sync 内存缓冲区无需刷新
+func (r *RingBuffer) Sync() error {
+	return nil
+}
+
+// Lines 按写入顺序返回缓冲区中的日志行
+func (r *RingBuffer) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	result := make([]string, 0, len(r.lines))
+	result = append(result, r.lines[r.next:]...)
+	return append(result, r.lines[:r.next]...)
+}
+
+// String 以换行拼接缓冲区中的日志行
+func (r *RingBuffer) String() string {
+	lines := r.Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Reset 清空缓冲区
+func (r *RingBuffer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.lines {
+		r.lines[i] = ""
+	}
+	r.next = 0
+	r.full = false
+}
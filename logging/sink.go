@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// -------------------------- 输出目标 --------------------------
+
+const (
+	EncodingConsole = "console" // 文本编码
+	EncodingJSON    = "json"    // JSON编码
+)
+
+// sinkSpec 描述单个输出目标的写入位置、级别与编码方式
+type sinkSpec struct {
+	writer     zapcore.WriteSyncer
+	level      string // 输出自身的级别，与日志器的主级别同时生效，空串表示只按主级别过滤
+	encoding   string
+	format     string // 文本编码时启用的键，支持"T(时间)L(级别)C(调用者)M(消息)"
+	timeLayout string
+	color      bool
+	syslog     *syslogWriter // 非空时按级别发送到 syslog，忽略 writer
+	ring       *RingBuffer   // 环形缓冲区输出，供日志器对外暴露
+}
+
+// newCore 根据输出目标描述创建 zap 核心，输出自身的级别作为主级别之外的第二道过滤，
+// 运行期通过 SetLevel 等修改主级别时同样对该输出生效
+func (spec sinkSpec) newCore(mainLevel zap.AtomicLevel) zapcore.Core {
+	var enabler zapcore.LevelEnabler = mainLevel
+	if spec.level != "" {
+		sinkLevel := parseLevel(spec.level)
+		enabler = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= sinkLevel && mainLevel.Enabled(lvl)
+		})
+	}
+	if spec.syslog != nil {
+		return &syslogCore{LevelEnabler: enabler, encoder: newSinkEncoder(spec), writer: spec.syslog}
+	}
+	return zapcore.NewCore(newSinkEncoder(spec), spec.writer, enabler)
+}
+
+// validateEncoding 校验编码名称
+func validateEncoding(encoding string) error {
+	switch strings.ToLower(encoding) {
+	case "", EncodingConsole, EncodingJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported log encoding: %s", encoding)
+	}
+}
+
+// consoleWriter 获取控制台输出目标
+func consoleWriter(output string) (*os.File, error) {
+	switch strings.ToLower(output) {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		return nil, fmt.Errorf("unsupported console output: %s", output)
+	}
+}
+
+// consoleSyncer 包装控制台文件，控制台写入不经过缓冲，
+// 对终端或管道执行 fsync 会返回 EINVAL 等错误，因此 Sync 直接忽略
+func consoleSyncer(f *os.File) zapcore.WriteSyncer {
+	return zapcore.Lock(zapcore.AddSync(struct{ io.Writer }{f}))
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// coreHolder 保存日志器当前使用的输出核心，日志器与其派生的子日志器共享同一个 coreHolder，
+// 重新配置或关闭日志器时整体替换核心，子日志器随之生效
+type coreHolder struct {
+	current atomic.Pointer[coreBox]
+}
+
+type coreBox struct {
+	core zapcore.Core
+}
+
+func newCoreHolder(core zapcore.Core) *coreHolder {
+	h := &coreHolder{}
+	h.store(core)
+	return h
+}
+
+func (h *coreHolder) load() *coreBox {
+	return h.current.Load()
+}
+
+func (h *coreHolder) store(core zapcore.Core) {
+	h.current.Store(&coreBox{core: core})
+}
+
+// swapCore 将调用转发给 coreHolder 中的当前核心，并在其上附加 With 添加的字段
+type swapCore struct {
+	holder *coreHolder
+	fields []zapcore.Field
+	cached atomic.Pointer[swapCache] // 附加字段后的核心，核心被替换后重新生成
+}
+
+type swapCache struct {
+	box  *coreBox
+	core zapcore.Core
+}
+
+func newSwapCore(holder *coreHolder) *swapCore {
+	return &swapCore{holder: holder}
+}
+
+// core 返回附加了字段的当前核心
+func (c *swapCore) core() zapcore.Core {
+	box := c.holder.load()
+	if len(c.fields) == 0 {
+		return box.core
+	}
+	if cached := c.cached.Load(); cached != nil && cached.box == box {
+		return cached.core
+	}
+	core := box.core.With(c.fields)
+	c.cached.Store(&swapCache{box: box, core: core})
+	return core
+}
+
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.core().Enabled(level)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &swapCore{holder: c.holder, fields: merged}
+}
+
+func (c *swapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.core().Check(entry, checked)
+}
+
+func (c *swapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(entry, fields)
+}
+
+func (c *swapCore) Sync() error {
+	return c.core().Sync()
+}
@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultSyslogNetwork  = "udp"
+	defaultSyslogAddress  = "127.0.0.1:514"
+	defaultSyslogFacility = 1 // user-level messages
+)
+
+// syslogWriter 通过 UDP 或 unix 套接字发送 RFC 3164 格式的 syslog 消息，不依赖 log/syslog，可跨平台使用
+type syslogWriter struct {
+	network  string
+	address  string
+	tag      string
+	hostname string
+	facility int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newSyslogWriter 创建 syslog 写入器并建立连接
+func newSyslogWriter(network, address, tag string, facility int) (*syslogWriter, error) {
+	if network == "" {
+		network = defaultSyslogNetwork
+	}
+	if address == "" {
+		address = defaultSyslogAddress
+	}
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	if facility <= 0 {
+		facility = defaultSyslogFacility
+	}
+	hostname, _ := os.Hostname()
+
+	w := &syslogWriter{
+		network:  network,
+		address:  address,
+		tag:      tag,
+		hostname: hostname,
+		facility: facility,
+	}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// connect 建立到 syslog 服务的连接
+func (w *syslogWriter) connect() error {
+	conn, err := net.Dial(w.network, w.address)
+	if err != nil {
+		return fmt.Errorf("connect syslog %s://%s error: %w", w.network, w.address, err)
+	}
+	w.conn = conn
+	return nil
+}
+
+// send 按日志级别计算优先级并发送一条消息，连接失效时重连一次
+func (w *syslogWriter) send(level zapcore.Level, msg []byte) error {
+	priority := w.facility*8 + syslogSeverity(level)
+	packet := fmt.Sprintf("<%d>%s %s %s[%d]: %s",
+		priority, time.Now().Format(time.Stamp), w.hostname, w.tag, os.Getpid(), msg)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if _, err := w.conn.Write([]byte(packet)); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	if err := w.connect(); err != nil {
+		return err
+	}
+	_, err := w.conn.Write([]byte(packet))
+	return err
+}
+
+// Close 关闭连接
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// syslogSeverity 将日志级别映射为 syslog 严重程度
+func syslogSeverity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.FatalLevel:
+		return 2 // crit
+	case level >= zapcore.ErrorLevel:
+		return 3 // err
+	case level >= zapcore.WarnLevel:
+		return 4 // warning
+	case level >= zapcore.InfoLevel:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// syslogCore 将每条日志编码后按级别发送到 syslog
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslogWriter
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &syslogCore{LevelEnabler: c.LevelEnabler, encoder: c.encoder.Clone(), writer: c.writer}
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return clone
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	return c.writer.send(entry.Level, buf.Bytes())
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
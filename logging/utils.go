@@ -1,7 +1,10 @@
 package logging
 
 import (
+	"errors"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,16 +14,36 @@ import (
 
 // 创建控制台编码器
 func newConsoleEncoder(format string) zapcore.Encoder {
+	return newSinkEncoder(sinkSpec{encoding: EncodingConsole, format: format})
+}
+
+// newSinkEncoder 根据输出目标描述创建编码器
+func newSinkEncoder(spec sinkSpec) zapcore.Encoder {
+	timeEncoder := zapcore.ISO8601TimeEncoder
+	if spec.timeLayout != "" {
+		timeEncoder = zapcore.TimeEncoderOfLayout(spec.timeLayout)
+	}
+
+	if strings.ToLower(spec.encoding) == EncodingJSON {
+		cfg := zap.NewProductionEncoderConfig()
+		cfg.EncodeTime = timeEncoder
+		return zapcore.NewJSONEncoder(cfg)
+	}
+
 	cfg := zapcore.EncoderConfig{
 		TimeKey:      "T",
 		LevelKey:     "L",
 		CallerKey:    "C",
 		MessageKey:   "M",
-		EncodeTime:   zapcore.ISO8601TimeEncoder,
+		EncodeTime:   timeEncoder,
 		EncodeLevel:  zapcore.CapitalLevelEncoder,
 		EncodeCaller: zapcore.ShortCallerEncoder,
 	}
+	if spec.color {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
 
+	format := spec.format
 	if !strings.Contains(format, "T") {
 		cfg.TimeKey = ""
 	}
@@ -37,6 +60,15 @@ func newConsoleEncoder(format string) zapcore.Encoder {
 	return zapcore.NewConsoleEncoder(cfg)
 }
 
+// parseLevel 解析日志级别字符串，无法识别时默认为info级别
+func parseLevel(level string) zapcore.Level {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return zapcore.InfoLevel
+	}
+	return lvl
+}
+
 // ensureDir 确保目录存在
 func ensureDir(filePath string) error {
 	dir := filepath.Dir(filePath)
@@ -45,3 +77,14 @@ func ensureDir(filePath string) error {
 	}
 	return nil
 }
+
+// closeAll 释放全部资源，返回合并后的关闭错误
+func closeAll(closers []io.Closer) error {
+	var errList []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return errors.Join(errList...)
+}
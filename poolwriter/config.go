@@ -0,0 +1,65 @@
+package poolwriter
+
+import (
+	"time"
+
+	"github.com/winezer0/xutils/liblog"
+)
+
+const (
+	defaultIdleTimeout     = 10 * time.Second
+	defaultMaxOpenFiles    = 128
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultMaxRetryBackoff = 5 * time.Second
+)
+
+// Config 定义写盘池的可配置项。
+type Config struct {
+	WorkerNum    int           // worker 数量，同一 StorePath 始终由同一个 worker 处理
+	QueueSize    int           // 队列总容量，按 worker 平均分配
+	IdleTimeout  time.Duration // 文件句柄空闲超过该时长后自动关闭
+	MaxOpenFiles int           // 单个 worker 最多同时保持打开的文件句柄数
+
+	MaxRetries      int                             // 写入失败后的最大重试次数，0 表示不重试
+	RetryBackoff    time.Duration                   // 首次重试前的等待时长，之后每次翻倍
+	MaxRetryBackoff time.Duration                   // 单次重试等待时长上限
+	DeadLetterFile  string                          // 最终失败的任务以 JSON 行追加到该文件，空串表示不记录
+	OnFailure       func(task StoreTask, err error) // 最终失败的任务回调，在 worker 协程中同步调用
+	Logger          liblog.Logger                   // 内部日志输出，为空时使用 SetLogger 设置的包级日志器
+}
+
+// defaultConfig 返回写盘池的默认配置。
+func defaultConfig(workerNum int, queueSize int) Config {
+	return Config{
+		WorkerNum:    workerNum,
+		QueueSize:    queueSize,
+		IdleTimeout:  defaultIdleTimeout,
+		MaxOpenFiles: defaultMaxOpenFiles,
+	}
+}
+
+// normalizeConfig 统一填充默认值。
+func normalizeConfig(cfg Config) Config {
+	if cfg.WorkerNum < 1 {
+		cfg.WorkerNum = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = cfg.WorkerNum * 2
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	if cfg.MaxOpenFiles < 1 {
+		cfg.MaxOpenFiles = defaultMaxOpenFiles
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxRetryBackoff < cfg.RetryBackoff {
+		cfg.MaxRetryBackoff = max(defaultMaxRetryBackoff, cfg.RetryBackoff)
+	}
+	return cfg
+}
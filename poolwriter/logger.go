@@ -0,0 +1,11 @@
+package poolwriter
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger Config.Logger 为空的写入池使用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置写入池任务失败、死信写入失败与关闭文件失败等错误的输出，Config.Logger 优先，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
@@ -1,21 +1,27 @@
 package poolwriter
 
 import (
-	"fmt"
+	"hash/fnv"
 	"sync"
 	"sync/atomic"
 
-	"github.com/winezer0/xutils/logging"
-	"github.com/winezer0/xutils/utils"
+	"github.com/winezer0/xutils/liblog"
 )
 
 // StoreTask 表示通用的写入任务，支持存储响应内容和缓存键写入。
 // 注意：WriteRaw=true 时始终使用覆盖模式写入，不支持追加。
+// Atomic=true 时原始内容先写入临时文件再重命名，读取方不会看到写了一半的文件。
+// Sink 非空时内容交给 Sink 处理，不再写入本地 StorePath。
 type StoreTask struct {
 	StorePath    string
 	StoreContent []byte
 	WriteRaw     bool
 	Overwrite    bool
+	Atomic       bool
+	Sink         Sink
+
+	done   chan error    // SubmitWait 用于接收任务最终结果
+	logger liblog.Logger // SubmitContext 时从 ctx 中取出的日志器
 }
 
 // NewStoreTask 创建原始数据写入文件任务
@@ -38,40 +44,85 @@ func NewStoreLine(cachePath string, cacheKey string) StoreTask {
 	}
 }
 
+// NewAtomicStoreTask 创建原子替换写入的原始数据任务。
+func NewAtomicStoreTask(storePath string, content []byte) StoreTask {
+	return StoreTask{
+		StorePath:    storePath,
+		StoreContent: content,
+		WriteRaw:     true,
+		Overwrite:    true,
+		Atomic:       true,
+	}
+}
+
+// NewSinkTask 创建写入指定 Sink 的任务。
+func NewSinkTask(sink Sink, storePath string, content []byte, writeRaw, overwrite bool) StoreTask {
+	return StoreTask{
+		StorePath:    storePath,
+		StoreContent: content,
+		WriteRaw:     writeRaw,
+		Overwrite:    overwrite,
+		Sink:         sink,
+	}
+}
+
 // Pool 表示通用写盘 worker 池。
+// 任务按 StorePath 的哈希分配到固定 worker，同一文件的写入天然串行，
+// worker 会保持文件句柄打开并合并连续的行写入，空闲句柄超时后自动关闭。
 type Pool struct {
-	taskCh    chan []StoreTask
-	wg        sync.WaitGroup
-	failCount atomic.Int64
+	config  Config
+	workers []*worker
+	wg      sync.WaitGroup
+	stats   poolStats
+
+	submitMu sync.RWMutex
+	stopped  bool
+	stopOnce sync.Once
+	quit     chan struct{}
+
+	successCount atomic.Int64
+	failCount    atomic.Int64
+	retryCount   atomic.Int64
+	openFiles    atomic.Int64
 }
 
 // NewPool 创建写盘池并启动指定数量 worker。
 func NewPool(workerNum int, queueSize int) *Pool {
-	if workerNum < 1 {
-		workerNum = 1
-	}
-	if queueSize < 1 {
-		queueSize = workerNum * 2
-	}
+	return NewPoolWithConfig(defaultConfig(workerNum, queueSize))
+}
 
+// NewPoolWithConfig 使用显式配置创建写盘池。
+func NewPoolWithConfig(cfg Config) *Pool {
+	cfg = normalizeConfig(cfg)
 	p := &Pool{
-		taskCh: make(chan []StoreTask, queueSize),
+		config:  cfg,
+		workers: make([]*worker, cfg.WorkerNum),
+		stats:   poolStats{pathFailures: make(map[string]int64)},
+		quit:    make(chan struct{}),
 	}
-	for i := 0; i < workerNum; i++ {
+	workerQueueSize := (cfg.QueueSize + cfg.WorkerNum - 1) / cfg.WorkerNum
+	for i := range p.workers {
+		p.workers[i] = newWorker(p, workerQueueSize)
+	}
+	for _, w := range p.workers {
 		p.wg.Add(1)
-		go p.runWorker()
+		go w.run()
 	}
 	return p
 }
 
-// Submit 投递单个写盘任务。
-func (p *Pool) Submit(task StoreTask) {
-	p.taskCh <- []StoreTask{task}
-}
-
-// StopAndWait 停止接收新任务并等待所有任务处理完成。
+// StopAndWait 停止接收新任务并等待所有任务处理完成，可重复调用。
 func (p *Pool) StopAndWait() {
-	close(p.taskCh)
+	p.stopOnce.Do(func() {
+		// 先唤醒阻塞中的投递方，再在写锁下关闭队列，避免向已关闭的通道发送
+		close(p.quit)
+		p.submitMu.Lock()
+		p.stopped = true
+		for _, w := range p.workers {
+			close(w.taskCh)
+		}
+		p.submitMu.Unlock()
+	})
 	p.wg.Wait()
 }
 
@@ -80,27 +131,24 @@ func (p *Pool) GetFailCount() int64 {
 	return p.failCount.Load()
 }
 
-// runWorker 执行写盘任务。
-func (p *Pool) runWorker() {
-	defer p.wg.Done()
-	for tasks := range p.taskCh {
-		for _, task := range tasks {
-			if err := writeTask(task); err != nil {
-				p.failCount.Add(1)
-				logging.Errorf("writer task failed: %v, store_path=%s", err, task.StorePath)
-			}
-		}
-	}
+// GetOpenFileCount 获取当前保持打开的文件句柄数量。
+func (p *Pool) GetOpenFileCount() int64 {
+	return p.openFiles.Load()
 }
 
-// writeTask 执行单个任务的写盘动作。
-func writeTask(task StoreTask) error {
-	if task.StorePath == "" {
-		return fmt.Errorf("store path is empty")
-	}
-	if task.WriteRaw {
-		return utils.WriteBytes(task.StorePath, task.StoreContent, task.Overwrite)
-	} else {
-		return utils.WriteLine(task.StorePath, string(task.StoreContent), task.Overwrite)
-	}
+// logger 获取写盘池使用的日志器，未单独设置时使用包级日志器。
+func (p *Pool) logger() liblog.Logger {
+	return liblog.Or(p.config.Logger, pkgLogger.Get())
+}
+
+// taskLogger 获取任务使用的日志器，投递时 ctx 携带日志器的任务优先使用该日志器。
+func (p *Pool) taskLogger(task StoreTask) liblog.Logger {
+	return liblog.Or(task.logger, p.logger())
+}
+
+// route 根据 StorePath 的哈希选择负责该文件的 worker。
+func (p *Pool) route(storePath string) *worker {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(storePath))
+	return p.workers[h.Sum32()%uint32(len(p.workers))]
 }
@@ -1,10 +1,19 @@
 package poolwriter
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/winezer0/xutils/liblog"
+	"github.com/winezer0/xutils/utils"
 )
 
 // TestPoolWriteStore 验证统一池能够正确写入响应文件。
@@ -232,3 +241,404 @@ func TestPoolFailCount(t *testing.T) {
 		t.Fatalf("expected at least 1 failure, got: %d", failCount)
 	}
 }
+
+// TestPoolSamePathOrdered 验证同一文件的行任务由同一 worker 串行写入且保持顺序。
+func TestPoolSamePathOrdered(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "lines.txt")
+
+	p := NewPool(4, 16)
+	for i := 0; i < 500; i++ {
+		p.Submit(NewStoreLine(storePath, fmt.Sprintf("line_%d", i)))
+	}
+	p.StopAndWait()
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("read store file failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 500 {
+		t.Fatalf("expected 500 lines, got: %d", len(lines))
+	}
+	for i, line := range lines {
+		if line != fmt.Sprintf("line_%d", i) {
+			t.Fatalf("unexpected line %d: %q", i, line)
+		}
+	}
+}
+
+// TestPoolIdleClose 验证空闲句柄超时后关闭，缓冲内容已经落盘。
+func TestPoolIdleClose(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "idle.txt")
+
+	p := NewPoolWithConfig(Config{WorkerNum: 1, QueueSize: 8, IdleTimeout: 20 * time.Millisecond})
+	defer p.StopAndWait()
+	p.Submit(NewStoreLine(storePath, "idle line"))
+
+	deadline := time.Now().Add(time.Second)
+	for !utils.FileExists(storePath) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	for p.GetOpenFileCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count := p.GetOpenFileCount(); count != 0 {
+		t.Fatalf("expected idle handle to be closed, open files: %d", count)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("read store file failed: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "idle line" {
+		t.Fatalf("unexpected store content: %q", string(data))
+	}
+}
+
+// TestPoolMaxOpenFiles 验证句柄数量超过上限时仍能正确写入所有文件。
+func TestPoolMaxOpenFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	p := NewPoolWithConfig(Config{WorkerNum: 1, QueueSize: 8, MaxOpenFiles: 2})
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 5; i++ {
+			p.Submit(NewStoreLine(filepath.Join(tmpDir, fmt.Sprintf("f%d.txt", i)), fmt.Sprintf("r%d", round)))
+		}
+	}
+	p.StopAndWait()
+
+	for i := 0; i < 5; i++ {
+		data, err := os.ReadFile(filepath.Join(tmpDir, fmt.Sprintf("f%d.txt", i)))
+		if err != nil {
+			t.Fatalf("read store file failed: %v", err)
+		}
+		if string(data) != "r0\nr1\nr2\n" {
+			t.Fatalf("unexpected content of f%d: %q", i, string(data))
+		}
+	}
+}
+
+// TestPoolSubmitWait 验证 SubmitWait 返回任务最终结果。
+func TestPoolSubmitWait(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "wait.txt")
+
+	p := NewPool(2, 8)
+	defer p.StopAndWait()
+
+	if err := p.SubmitWait(NewStoreLine(storePath, "wait line")); err != nil {
+		t.Fatalf("expected nil error, got: %v", err)
+	}
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("read store file failed: %v", err)
+	}
+	if string(data) != "wait line\n" {
+		t.Fatalf("unexpected store content: %q", string(data))
+	}
+
+	if err := p.SubmitWait(NewStoreTask(string([]byte{0x00}), []byte("x"), true, true)); err == nil {
+		t.Fatal("expected error for invalid path")
+	}
+}
+
+// TestPoolRetryAndDeadLetter 验证失败任务按配置重试，并写入死信文件和回调。
+func TestPoolRetryAndDeadLetter(t *testing.T) {
+	tmpDir := t.TempDir()
+	deadLetterFile := filepath.Join(tmpDir, "dead.jsonl")
+	invalidPath := string([]byte{0x00})
+
+	var mu sync.Mutex
+	var callbackTasks []StoreTask
+	p := NewPoolWithConfig(Config{
+		WorkerNum:      1,
+		QueueSize:      8,
+		MaxRetries:     2,
+		RetryBackoff:   time.Millisecond,
+		DeadLetterFile: deadLetterFile,
+		OnFailure: func(task StoreTask, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			callbackTasks = append(callbackTasks, task)
+		},
+	})
+	p.Submit(NewStoreTask(invalidPath, []byte("raw body"), true, true))
+	p.Submit(NewStoreLine(invalidPath, "line body"))
+	p.Submit(NewStoreLine(filepath.Join(tmpDir, "ok.txt"), "ok"))
+	p.StopAndWait()
+
+	stats := p.Stats()
+	if stats.Failed != 2 || stats.Succeeded != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.Retried != 4 {
+		t.Fatalf("expected 4 retries, got: %d", stats.Retried)
+	}
+	if stats.PathFailures[invalidPath] != 2 {
+		t.Fatalf("unexpected path failures: %v", stats.PathFailures)
+	}
+	if len(callbackTasks) != 2 {
+		t.Fatalf("expected 2 callback tasks, got: %d", len(callbackTasks))
+	}
+
+	letters, err := ReadDeadLetters(deadLetterFile)
+	if err != nil {
+		t.Fatalf("read dead letters failed: %v", err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("expected 2 dead letters, got: %d", len(letters))
+	}
+	if string(letters[0].StoreContent) != "raw body" || !letters[0].WriteRaw || letters[0].Error == "" {
+		t.Fatalf("unexpected dead letter: %+v", letters[0])
+	}
+	if task := letters[1].Task(); task.StorePath != invalidPath || string(task.StoreContent) != "line body" {
+		t.Fatalf("unexpected replay task: %+v", task)
+	}
+}
+
+// TestPoolSubmitAfterStop 验证停止后投递返回 ErrPoolStopped 而不是 panic。
+func TestPoolSubmitAfterStop(t *testing.T) {
+	p := NewPool(1, 8)
+	p.StopAndWait()
+	p.StopAndWait()
+
+	task := NewStoreLine(filepath.Join(t.TempDir(), "stopped.txt"), "x")
+	if err := p.Submit(task); !errors.Is(err, ErrPoolStopped) {
+		t.Fatalf("expected ErrPoolStopped, got: %v", err)
+	}
+	if err := p.SubmitBatch([]StoreTask{task}); !errors.Is(err, ErrPoolStopped) {
+		t.Fatalf("expected ErrPoolStopped, got: %v", err)
+	}
+	if err := p.SubmitWait(task); !errors.Is(err, ErrPoolStopped) {
+		t.Fatalf("expected ErrPoolStopped, got: %v", err)
+	}
+	if p.TrySubmit(task) {
+		t.Fatal("expected TrySubmit to fail after stop")
+	}
+}
+
+// TestPoolTrySubmitAndContext 验证队列已满时非阻塞投递失败、带超时的投递返回 ctx 错误。
+func TestPoolTrySubmitAndContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	p := NewPoolWithConfig(Config{
+		WorkerNum: 1,
+		QueueSize: 1,
+		OnFailure: func(task StoreTask, err error) {
+			close(blocked)
+			<-release
+		},
+	})
+
+	// 第一个任务失败后在回调中阻塞 worker，第二个任务占满队列
+	if err := p.Submit(NewStoreTask("", nil, true, true)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	<-blocked
+	if !p.TrySubmit(NewStoreLine(filepath.Join(tmpDir, "a.txt"), "a")) {
+		t.Fatal("expected TrySubmit to succeed on empty queue")
+	}
+	if p.TrySubmit(NewStoreLine(filepath.Join(tmpDir, "a.txt"), "b")) {
+		t.Fatal("expected TrySubmit to fail on full queue")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.SubmitContext(ctx, NewStoreLine(filepath.Join(tmpDir, "a.txt"), "c")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+
+	close(release)
+	p.StopAndWait()
+	data, err := os.ReadFile(filepath.Join(tmpDir, "a.txt"))
+	if err != nil {
+		t.Fatalf("read store file failed: %v", err)
+	}
+	if string(data) != "a\n" {
+		t.Fatalf("unexpected store content: %q", string(data))
+	}
+}
+
+// TestPoolSubmitBatch 验证批量投递后各文件内容完整且顺序不变。
+func TestPoolSubmitBatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	var tasks []StoreTask
+	for i := 0; i < 50; i++ {
+		tasks = append(tasks, NewStoreLine(filepath.Join(tmpDir, fmt.Sprintf("f%d.txt", i%3)), fmt.Sprintf("%d", i)))
+	}
+
+	p := NewPool(3, 4)
+	if err := p.SubmitBatch(tasks); err != nil {
+		t.Fatalf("submit batch failed: %v", err)
+	}
+	p.StopAndWait()
+
+	for f := 0; f < 3; f++ {
+		var expected strings.Builder
+		for i := f; i < 50; i += 3 {
+			expected.WriteString(fmt.Sprintf("%d\n", i))
+		}
+		data, err := os.ReadFile(filepath.Join(tmpDir, fmt.Sprintf("f%d.txt", f)))
+		if err != nil {
+			t.Fatalf("read store file failed: %v", err)
+		}
+		if string(data) != expected.String() {
+			t.Fatalf("unexpected content of f%d: %q", f, string(data))
+		}
+	}
+}
+
+// recordLogger 记录错误日志，用于验证写盘池使用注入的日志器
+type recordLogger struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (r *recordLogger) Debugf(string, ...interface{}) {}
+func (r *recordLogger) Infof(string, ...interface{})  {}
+func (r *recordLogger) Warnf(string, ...interface{})  {}
+func (r *recordLogger) Errorf(template string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(template, args...))
+}
+
+// TestPoolLogger 验证失败日志输出到配置的日志器。
+func TestPoolLogger(t *testing.T) {
+	rec := &recordLogger{}
+	p := NewPoolWithConfig(Config{WorkerNum: 1, QueueSize: 4, Logger: rec})
+	p.Submit(NewStoreLine("", "x"))
+	p.StopAndWait()
+
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "store path is empty") {
+		t.Fatalf("unexpected error logs: %v", rec.errors)
+	}
+}
+
+// TestPoolContextLogger 验证 ctx 中的日志器优先于写盘池配置的日志器。
+func TestPoolContextLogger(t *testing.T) {
+	poolLog := &recordLogger{}
+	ctxLog := &recordLogger{}
+	p := NewPoolWithConfig(Config{WorkerNum: 1, QueueSize: 4, Logger: poolLog})
+	ctx := liblog.WithContext(context.Background(), ctxLog)
+	if err := p.SubmitContext(ctx, NewStoreLine("", "x")); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	p.StopAndWait()
+
+	if len(ctxLog.errors) != 1 || len(poolLog.errors) != 0 {
+		t.Fatalf("unexpected error logs: ctx=%v pool=%v", ctxLog.errors, poolLog.errors)
+	}
+}
+
+// flakySink 前 failures 次写入失败的写入目标。
+type flakySink struct {
+	MemorySink
+	failures atomic.Int32
+}
+
+func (s *flakySink) WriteTask(task StoreTask) error {
+	if s.failures.Add(-1) >= 0 {
+		return errors.New("temporary failure")
+	}
+	return s.MemorySink.WriteTask(task)
+}
+
+// TestPoolRetryNotBlocking 验证重试等待期间同一 worker 上的其他路径照常写入，同一路径的后续任务保持顺序。
+func TestPoolRetryNotBlocking(t *testing.T) {
+	tmpDir := t.TempDir()
+	sink := &flakySink{MemorySink: MemorySink{files: make(map[string][]byte)}}
+	sink.failures.Store(2)
+	p := NewPoolWithConfig(Config{
+		WorkerNum:    1,
+		QueueSize:    8,
+		MaxRetries:   3,
+		RetryBackoff: 200 * time.Millisecond,
+	})
+	p.Submit(NewSinkTask(sink, "a.txt", []byte("first"), false, false))
+	p.Submit(NewSinkTask(sink, "a.txt", []byte("second"), false, false))
+
+	start := time.Now()
+	if err := p.SubmitWait(NewStoreLine(filepath.Join(tmpDir, "b.txt"), "b")); err != nil {
+		t.Fatalf("write other path failed: %v", err)
+	}
+	if cost := time.Since(start); cost > 150*time.Millisecond {
+		t.Fatalf("other path blocked by retry for %v", cost)
+	}
+	p.StopAndWait()
+
+	if data, _ := sink.Get("a.txt"); string(data) != "first\nsecond\n" {
+		t.Fatalf("unexpected sink content: %q", data)
+	}
+	if stats := p.Stats(); stats.Retried != 2 || stats.Failed != 0 || stats.Succeeded != 3 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+// TestPoolRetryAfterPartialFlush 验证刷新失败后重试先截断已部分落盘的内容，不会重复写入。
+func TestPoolRetryAfterPartialFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "partial.txt")
+	p := NewPoolWithConfig(Config{WorkerNum: 1, MaxRetries: 1, RetryBackoff: time.Millisecond})
+	defer p.StopAndWait()
+	w := newWorker(p, 1)
+
+	w.processTask(NewStoreLine(path, "line1"))
+	w.flushAll()
+	w.processTask(NewStoreLine(path, "line2"))
+	// 模拟缓冲区部分落盘后写入失败
+	of := w.files[path]
+	if _, err := of.file.WriteString("li"); err != nil {
+		t.Fatalf("write partial content failed: %v", err)
+	}
+	_ = of.file.Close()
+	w.flushAll()
+	if len(w.retries) != 1 {
+		t.Fatalf("expected 1 scheduled retry, got %d", len(w.retries))
+	}
+
+	w.runRetries(time.Now().Add(time.Second))
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "line1\nline2\n" {
+		t.Fatalf("unexpected file content: %q, %v", data, err)
+	}
+}
+
+// TestPoolRetryBeforeRawAndOverwrite 测试刷新失败后到达的原始写入与覆盖写入排在重试之后执行
+func TestPoolRetryBeforeRawAndOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	p := NewPoolWithConfig(Config{WorkerNum: 1, MaxRetries: 1, RetryBackoff: time.Millisecond})
+	defer p.StopAndWait()
+
+	cases := []struct {
+		name     string
+		task     func(path string) StoreTask
+		expected string
+	}{
+		{"raw", func(path string) StoreTask { return NewStoreTask(path, []byte("raw\n"), true, false) }, "line1\nline2\nraw\n"},
+		{"overwrite", func(path string) StoreTask { return NewStoreTask(path, []byte("new"), false, true) }, "new\n"},
+	}
+	for _, tc := range cases {
+		path := filepath.Join(dir, tc.name+".txt")
+		w := newWorker(p, 1)
+		w.processTask(NewStoreLine(path, "line1"))
+		w.flushAll()
+		w.processTask(NewStoreLine(path, "line2"))
+		// 模拟句柄失效，使下一次刷新失败
+		_ = w.files[path].file.Close()
+
+		w.processTask(tc.task(path))
+		if len(w.retries) != 1 || len(w.retries[path].held) != 1 {
+			t.Fatalf("%s: expected task held behind retry, got %d retries", tc.name, len(w.retries))
+		}
+		w.runRetries(time.Now().Add(time.Second))
+		w.flushAll()
+		w.closeAll()
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != tc.expected {
+			t.Errorf("%s: unexpected file content: %q, %v", tc.name, data, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package poolwriter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// Stats 表示写盘池的运行统计快照。
+type Stats struct {
+	Succeeded    int64            // 成功写入的任务数
+	Failed       int64            // 重试后仍失败的任务数
+	Retried      int64            // 累计重试次数
+	OpenFiles    int64            // 当前保持打开的文件句柄数
+	PathFailures map[string]int64 // 按 StorePath 统计的失败任务数
+}
+
+// DeadLetter 表示写入死信文件的失败任务记录，任务的 Sink 无法序列化，不会被记录。
+type DeadLetter struct {
+	StorePath    string    `json:"store_path"`
+	StoreContent []byte    `json:"store_content"`
+	WriteRaw     bool      `json:"write_raw"`
+	Overwrite    bool      `json:"overwrite"`
+	Atomic       bool      `json:"atomic"`
+	Error        string    `json:"error"`
+	FailedAt     time.Time `json:"failed_at"`
+}
+
+// Task 将死信记录还原为可重新投递的写盘任务。
+func (d DeadLetter) Task() StoreTask {
+	task := NewStoreTask(d.StorePath, d.StoreContent, d.WriteRaw, d.Overwrite)
+	task.Atomic = d.Atomic
+	return task
+}
+
+// ReadDeadLetters 读取死信文件中的全部失败任务记录。
+func ReadDeadLetters(path string) ([]DeadLetter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return letters, fmt.Errorf("parse dead letter line %d error: %w", lineNum, err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, scanner.Err()
+}
+
+// poolStats 保存需要加锁维护的统计信息。
+type poolStats struct {
+	mu           sync.Mutex
+	pathFailures map[string]int64
+	deadLetterMu sync.Mutex
+}
+
+// Stats 获取写盘池的统计快照。
+func (p *Pool) Stats() Stats {
+	p.stats.mu.Lock()
+	pathFailures := make(map[string]int64, len(p.stats.pathFailures))
+	for path, count := range p.stats.pathFailures {
+		pathFailures[path] = count
+	}
+	p.stats.mu.Unlock()
+
+	return Stats{
+		Succeeded:    p.successCount.Load(),
+		Failed:       p.failCount.Load(),
+		Retried:      p.retryCount.Load(),
+		OpenFiles:    p.openFiles.Load(),
+		PathFailures: pathFailures,
+	}
+}
+
+// complete 汇报任务最终结果，失败时更新统计并交给死信文件和回调处理。
+func (p *Pool) complete(task StoreTask, err error) {
+	if err == nil {
+		p.successCount.Add(1)
+	} else {
+		p.reportFailure(task, err)
+	}
+	if task.done != nil {
+		task.done <- err
+	}
+}
+
+// reportFailure 记录失败任务。
+func (p *Pool) reportFailure(task StoreTask, err error) {
+	p.failCount.Add(1)
+	p.stats.mu.Lock()
+	p.stats.pathFailures[task.StorePath]++
+	p.stats.mu.Unlock()
+	p.taskLogger(task).Errorf("writer task failed: %v, store_path=%s", err, task.StorePath)
+
+	if p.config.DeadLetterFile != "" {
+		if dlErr := p.writeDeadLetter(task, err); dlErr != nil {
+			p.taskLogger(task).Errorf("write dead letter failed: %v, dead_letter_file=%s", dlErr, p.config.DeadLetterFile)
+		}
+	}
+	if p.config.OnFailure != nil {
+		p.config.OnFailure(task, err)
+	}
+}
+
+// writeDeadLetter 将失败任务以 JSON 行追加到死信文件。
+func (p *Pool) writeDeadLetter(task StoreTask, err error) error {
+	letter := DeadLetter{
+		StorePath:    task.StorePath,
+		StoreContent: task.StoreContent,
+		WriteRaw:     task.WriteRaw,
+		Overwrite:    task.Overwrite,
+		Atomic:       task.Atomic,
+		Error:        err.Error(),
+		FailedAt:     time.Now(),
+	}
+	line, mErr := json.Marshal(letter)
+	if mErr != nil {
+		return mErr
+	}
+
+	p.stats.deadLetterMu.Lock()
+	defer p.stats.deadLetterMu.Unlock()
+	return utils.WriteLine(p.config.DeadLetterFile, string(line), false)
+}
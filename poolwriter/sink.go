@@ -0,0 +1,92 @@
+package poolwriter
+
+import (
+	"sort"
+	"sync"
+)
+
+// Sink 表示写盘任务的写入目标。
+// 设置了 Sink 的任务不再写入本地 StorePath，而是交给 Sink 处理，StorePath 仅作为目标内的名称。
+// 同一 Sink 可能被多个 worker 同时调用，实现需自行保证并发安全；Sink 由调用方在 StopAndWait 之后关闭。
+// 按内容摘要去重存储可使用 casstore.NewSink。
+type Sink interface {
+	WriteTask(task StoreTask) error
+	Close() error
+}
+
+// RetryableSink 是 Sink 的可选接口，用于声明写入失败后能否重试。
+// 追加写入压缩流或归档的 Sink 失败时可能已写入部分内容，重试会产生损坏的数据，应返回 false；
+// 未实现该接口的 Sink 按配置重试。
+type RetryableSink interface {
+	Retryable() bool
+}
+
+// sinkRetryable 判断 Sink 写入失败后能否重试。
+func sinkRetryable(sink Sink) bool {
+	if r, ok := sink.(RetryableSink); ok {
+		return r.Retryable()
+	}
+	return true
+}
+
+// taskPayload 返回任务实际写入的内容，行任务自动追加换行符。
+func taskPayload(task StoreTask) []byte {
+	if task.WriteRaw {
+		return task.StoreContent
+	}
+	payload := make([]byte, 0, len(task.StoreContent)+1)
+	payload = append(payload, task.StoreContent...)
+	return append(payload, '\n')
+}
+
+// MemorySink 将任务内容保存在内存中，主要用于测试。
+type MemorySink struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemorySink 创建内存写入目标。
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: make(map[string][]byte)}
+}
+
+// WriteTask 按 Overwrite 覆盖或追加内存中的内容。
+func (s *MemorySink) WriteTask(task StoreTask) error {
+	payload := taskPayload(task)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.Overwrite {
+		s.files[task.StorePath] = append([]byte(nil), payload...)
+	} else {
+		s.files[task.StorePath] = append(s.files[task.StorePath], payload...)
+	}
+	return nil
+}
+
+// Get 获取指定路径的内容副本。
+func (s *MemorySink) Get(storePath string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.files[storePath]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), data...), true
+}
+
+// Paths 获取已写入的全部路径，按字典序排列。
+func (s *MemorySink) Paths() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	paths := make([]string, 0, len(s.files))
+	for path := range s.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Close 内存目标无需释放资源。
+func (s *MemorySink) Close() error {
+	return nil
+}
@@ -0,0 +1,156 @@
+package poolwriter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// archiveEntryName 将 StorePath 转换为归档内的条目名称。
+func archiveEntryName(storePath string) string {
+	name := strings.TrimPrefix(storePath, filepath.VolumeName(storePath))
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(name)), "/")
+}
+
+// TarSink 将每个任务作为一个条目写入 tar 归档，可选 gzip 压缩。
+// 归档条目不可追加，同一 StorePath 多次写入会产生多个同名条目，解包时以最后一个为准。
+type TarSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	gz     *gzip.Writer
+	writer *tar.Writer
+}
+
+// NewTarSink 创建 tar 归档写入目标，compress 为 true 时输出 tar.gz。
+func NewTarSink(archivePath string, compress bool) (*TarSink, error) {
+	if err := utils.EnsureDir(archivePath, true); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("create tar archive error: %w", err)
+	}
+	s := &TarSink{file: f}
+	if compress {
+		s.gz = gzip.NewWriter(f)
+		s.writer = tar.NewWriter(s.gz)
+	} else {
+		s.writer = tar.NewWriter(f)
+	}
+	return s, nil
+}
+
+// WriteTask 将任务内容写入一个 tar 条目。
+func (s *TarSink) WriteTask(task StoreTask) error {
+	payload := taskPayload(task)
+	header := &tar.Header{
+		Name:    archiveEntryName(task.StorePath),
+		Mode:    0644,
+		Size:    int64(len(payload)),
+		ModTime: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.writer.WriteHeader(header); err != nil {
+		return fmt.Errorf("write tar header error: %w", err)
+	}
+	if _, err := s.writer.Write(payload); err != nil {
+		return fmt.Errorf("write tar entry error: %w", err)
+	}
+	return nil
+}
+
+// Retryable 条目写入失败后不能重试，重试会在写了一半的条目后继续写入。
+func (s *TarSink) Retryable() bool {
+	return false
+}
+
+// Close 写入归档结尾并关闭文件。
+func (s *TarSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errList []error
+	if err := s.writer.Close(); err != nil {
+		errList = append(errList, fmt.Errorf("close tar writer error: %w", err))
+	}
+	if s.gz != nil {
+		if err := s.gz.Close(); err != nil {
+			errList = append(errList, fmt.Errorf("close gzip writer error: %w", err))
+		}
+	}
+	if err := s.file.Close(); err != nil {
+		errList = append(errList, fmt.Errorf("close tar archive error: %w", err))
+	}
+	return errors.Join(errList...)
+}
+
+// ZipSink 将每个任务作为一个条目写入 zip 归档。
+// 归档条目不可追加，同一 StorePath 多次写入会产生多个同名条目。
+type ZipSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *zip.Writer
+}
+
+// NewZipSink 创建 zip 归档写入目标。
+func NewZipSink(archivePath string) (*ZipSink, error) {
+	if err := utils.EnsureDir(archivePath, true); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("create zip archive error: %w", err)
+	}
+	return &ZipSink{file: f, writer: zip.NewWriter(f)}, nil
+}
+
+// WriteTask 将任务内容压缩写入一个 zip 条目。
+func (s *ZipSink) WriteTask(task StoreTask) error {
+	header := &zip.FileHeader{
+		Name:     archiveEntryName(task.StorePath),
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, err := s.writer.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("create zip entry error: %w", err)
+	}
+	if _, err := w.Write(taskPayload(task)); err != nil {
+		return fmt.Errorf("write zip entry error: %w", err)
+	}
+	return nil
+}
+
+// Retryable 条目写入失败后不能重试，重试会留下写了一半的条目。
+func (s *ZipSink) Retryable() bool {
+	return false
+}
+
+// Close 写入中央目录并关闭文件。
+func (s *ZipSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errList []error
+	if err := s.writer.Close(); err != nil {
+		errList = append(errList, fmt.Errorf("close zip writer error: %w", err))
+	}
+	if err := s.file.Close(); err != nil {
+		errList = append(errList, fmt.Errorf("close zip archive error: %w", err))
+	}
+	return errors.Join(errList...)
+}
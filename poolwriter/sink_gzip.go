@@ -0,0 +1,100 @@
+package poolwriter
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// GzipSink 将任务内容写入 gzip 压缩文件，文件路径为 StorePath 加上 Suffix。
+// 每个文件的压缩流保持打开直到 Close，追加写入已存在的文件时会新增一个 gzip 成员，标准解压工具可以直接读取。
+type GzipSink struct {
+	Suffix string
+
+	mu      sync.Mutex
+	writers map[string]*gzipFile
+}
+
+type gzipFile struct {
+	file   *os.File
+	writer *gzip.Writer
+}
+
+// NewGzipSink 创建 gzip 写入目标，suffix 为空时使用 ".gz"。
+func NewGzipSink(suffix string) *GzipSink {
+	if suffix == "" {
+		suffix = ".gz"
+	}
+	return &GzipSink{
+		Suffix:  suffix,
+		writers: make(map[string]*gzipFile),
+	}
+}
+
+// WriteTask 写入压缩内容，Overwrite=true 时先截断已有文件。
+func (s *GzipSink) WriteTask(task StoreTask) error {
+	path := task.StorePath + s.Suffix
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gf, ok := s.writers[path]
+	if ok && task.Overwrite {
+		delete(s.writers, path)
+		if err := gf.close(); err != nil {
+			return err
+		}
+		ok = false
+	}
+	if !ok {
+		if err := utils.EnsureDir(path, true); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, utils.ParseFlagFromOver(task.Overwrite), 0644)
+		if err != nil {
+			return err
+		}
+		gf = &gzipFile{file: f, writer: gzip.NewWriter(f)}
+		s.writers[path] = gf
+	}
+
+	if _, err := gf.writer.Write(taskPayload(task)); err != nil {
+		delete(s.writers, path)
+		_ = gf.close()
+		return fmt.Errorf("write gzip file error: %w", err)
+	}
+	return nil
+}
+
+// Retryable 压缩流写入失败后不能重试，避免在损坏的 gzip 成员后继续追加。
+func (s *GzipSink) Retryable() bool {
+	return false
+}
+
+// Close 结束所有压缩流并关闭文件。
+func (s *GzipSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errList []error
+	for path, gf := range s.writers {
+		if err := gf.close(); err != nil {
+			errList = append(errList, fmt.Errorf("close gzip file '%s' error: %w", path, err))
+		}
+	}
+	s.writers = make(map[string]*gzipFile)
+	return errors.Join(errList...)
+}
+
+// close 结束压缩流并关闭文件。
+func (gf *gzipFile) close() error {
+	err := gf.writer.Close()
+	if cErr := gf.file.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package poolwriter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMemorySink 验证内存目标按 Overwrite 覆盖或追加内容。
+func TestMemorySink(t *testing.T) {
+	sink := NewMemorySink()
+	p := NewPool(2, 8)
+	p.Submit(NewSinkTask(sink, "a", []byte("line1"), false, false))
+	p.Submit(NewSinkTask(sink, "a", []byte("line2"), false, false))
+	p.Submit(NewSinkTask(sink, "b", []byte("old"), true, true))
+	p.Submit(NewSinkTask(sink, "b", []byte("new"), true, true))
+	p.StopAndWait()
+
+	if data, _ := sink.Get("a"); string(data) != "line1\nline2\n" {
+		t.Fatalf("unexpected content of a: %q", string(data))
+	}
+	if data, _ := sink.Get("b"); string(data) != "new" {
+		t.Fatalf("unexpected content of b: %q", string(data))
+	}
+	if paths := sink.Paths(); len(paths) != 2 || paths[0] != "a" || paths[1] != "b" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+}
+
+// TestGzipSink 验证 gzip 目标写入后可以完整解压，追加写入产生的多成员文件也能读取。
+func TestGzipSink(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "out.txt")
+
+	for _, line := range []string{"first", "second"} {
+		sink := NewGzipSink("")
+		p := NewPool(1, 8)
+		p.Submit(NewSinkTask(sink, storePath, []byte(line), false, false))
+		p.StopAndWait()
+		if err := sink.Close(); err != nil {
+			t.Fatalf("close gzip sink failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(storePath + ".gz")
+	if err != nil {
+		t.Fatalf("open gzip file failed: %v", err)
+	}
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("create gzip reader failed: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read gzip file failed: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Fatalf("unexpected gzip content: %q", string(data))
+	}
+}
+
+// TestTarSink 验证 tar 目标将每个任务写为一个条目。
+func TestTarSink(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "out.tar.gz")
+	sink, err := NewTarSink(archivePath, true)
+	if err != nil {
+		t.Fatalf("create tar sink failed: %v", err)
+	}
+	p := NewPool(2, 8)
+	p.Submit(NewSinkTask(sink, "/host/a.txt", []byte("body a"), true, true))
+	p.Submit(NewSinkTask(sink, "host/b.txt", []byte("body b"), true, true))
+	p.StopAndWait()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close tar sink failed: %v", err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatalf("open archive failed: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("create gzip reader failed: %v", err)
+	}
+	entries := make(map[string]string)
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read tar entry failed: %v", err)
+		}
+		data, _ := io.ReadAll(reader)
+		entries[header.Name] = string(data)
+	}
+	if entries["host/a.txt"] != "body a" || entries["host/b.txt"] != "body b" {
+		t.Fatalf("unexpected tar entries: %v", entries)
+	}
+}
+
+// TestZipSink 验证 zip 目标将每个任务写为一个条目。
+func TestZipSink(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "out.zip")
+	sink, err := NewZipSink(archivePath)
+	if err != nil {
+		t.Fatalf("create zip sink failed: %v", err)
+	}
+	p := NewPool(2, 8)
+	p.Submit(NewSinkTask(sink, "a.txt", []byte("body a"), true, true))
+	p.Submit(NewSinkTask(sink, "b.txt", []byte("line b"), false, false))
+	p.StopAndWait()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close zip sink failed: %v", err)
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatalf("open zip failed: %v", err)
+	}
+	defer reader.Close()
+	entries := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open zip entry failed: %v", err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		entries[file.Name] = string(data)
+	}
+	if entries["a.txt"] != "body a" || entries["b.txt"] != "line b\n" {
+		t.Fatalf("unexpected zip entries: %v", entries)
+	}
+}
+
+// TestPoolAtomicWrite 验证原子写入替换目标文件且不残留临时文件。
+func TestPoolAtomicWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "body.bin")
+	if err := os.WriteFile(storePath, []byte("old content that is longer"), 0644); err != nil {
+		t.Fatalf("prepare file failed: %v", err)
+	}
+
+	p := NewPool(1, 8)
+	if err := p.SubmitWait(NewAtomicStoreTask(storePath, []byte("new"))); err != nil {
+		t.Fatalf("atomic write failed: %v", err)
+	}
+	p.StopAndWait()
+
+	data, err := os.ReadFile(storePath)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("unexpected content: %q, %v", string(data), err)
+	}
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected no temp files left, got: %d entries", len(entries))
+	}
+}
+
+// brokenArchiveSink 总是写入失败且声明不可重试的写入目标。
+type brokenArchiveSink struct {
+	MemorySink
+	writes atomic.Int32
+}
+
+func (s *brokenArchiveSink) WriteTask(task StoreTask) error {
+	s.writes.Add(1)
+	return errors.New("archive broken")
+}
+
+func (s *brokenArchiveSink) Retryable() bool {
+	return false
+}
+
+// TestSinkNotRetryable 验证声明不可重试的 Sink 失败后不再重试。
+func TestSinkNotRetryable(t *testing.T) {
+	sink := &brokenArchiveSink{}
+	p := NewPoolWithConfig(Config{WorkerNum: 1, MaxRetries: 3, RetryBackoff: time.Millisecond})
+	if err := p.SubmitWait(NewSinkTask(sink, "a.txt", []byte("body"), true, true)); err == nil {
+		t.Fatal("expected sink error")
+	}
+	p.StopAndWait()
+	if sink.writes.Load() != 1 || p.Stats().Retried != 0 {
+		t.Fatalf("unexpected retries: writes %d, stats %+v", sink.writes.Load(), p.Stats())
+	}
+}
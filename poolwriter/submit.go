@@ -0,0 +1,94 @@
+package poolwriter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/winezer0/xutils/liblog"
+)
+
+// ErrPoolStopped 表示写盘池已经停止，不再接收新任务。
+var ErrPoolStopped = errors.New("writer pool stopped")
+
+// Submit 投递单个写盘任务，队列满时阻塞等待，写盘池已停止时返回 ErrPoolStopped。
+func (p *Pool) Submit(task StoreTask) error {
+	return p.SubmitContext(context.Background(), task)
+}
+
+// SubmitContext 投递单个写盘任务，队列满时阻塞直到 ctx 结束。
+// ctx 中保存的日志器用于输出该任务的失败日志，便于区分不同目标的写盘错误。
+func (p *Pool) SubmitContext(ctx context.Context, task StoreTask) error {
+	task.logger = liblog.FromContext(ctx, task.logger)
+	return p.send(ctx, p.route(task.StorePath), []StoreTask{task})
+}
+
+// TrySubmit 非阻塞地投递单个写盘任务，队列已满或写盘池已停止时返回 false。
+func (p *Pool) TrySubmit(task StoreTask) bool {
+	p.submitMu.RLock()
+	defer p.submitMu.RUnlock()
+	if p.stopped {
+		return false
+	}
+	select {
+	case p.route(task.StorePath).taskCh <- []StoreTask{task}:
+		return true
+	default:
+		return false
+	}
+}
+
+// SubmitBatch 批量投递写盘任务，按 worker 分组后整组入队，同一文件的任务保持原有顺序。
+func (p *Pool) SubmitBatch(tasks []StoreTask) error {
+	return p.SubmitBatchContext(context.Background(), tasks)
+}
+
+// SubmitBatchContext 批量投递写盘任务，队列满时阻塞直到 ctx 结束。
+// 返回错误时，排在出错分组之前的任务可能已经入队。
+func (p *Pool) SubmitBatchContext(ctx context.Context, tasks []StoreTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ctxLogger := liblog.FromContext(ctx, nil)
+	groups := make(map[*worker][]StoreTask)
+	var order []*worker
+	for _, task := range tasks {
+		task.logger = liblog.Or(ctxLogger, task.logger)
+		w := p.route(task.StorePath)
+		if _, ok := groups[w]; !ok {
+			order = append(order, w)
+		}
+		groups[w] = append(groups[w], task)
+	}
+	for _, w := range order {
+		if err := p.send(ctx, w, groups[w]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SubmitWait 投递单个写盘任务并等待其落盘，返回重试后的最终错误。
+func (p *Pool) SubmitWait(task StoreTask) error {
+	task.done = make(chan error, 1)
+	if err := p.Submit(task); err != nil {
+		return err
+	}
+	return <-task.done
+}
+
+// send 将一组任务发送到指定 worker 的队列。
+func (p *Pool) send(ctx context.Context, w *worker, tasks []StoreTask) error {
+	p.submitMu.RLock()
+	defer p.submitMu.RUnlock()
+	if p.stopped {
+		return ErrPoolStopped
+	}
+	select {
+	case w.taskCh <- tasks:
+		return nil
+	case <-p.quit:
+		return ErrPoolStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
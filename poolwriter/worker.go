@@ -0,0 +1,395 @@
+package poolwriter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/winezer0/xutils/utils"
+)
+
+// openFile 表示 worker 持有的文件句柄及其尚未落盘的行任务。
+type openFile struct {
+	file     *os.File
+	writer   *bufio.Writer
+	pending  []StoreTask
+	offset   int64 // 最近一次成功刷新后的文件长度，刷新失败时截断到该位置再重写 pending
+	buffered int64 // 最近一次成功刷新后写入缓冲区的字节数
+	lastUsed time.Time
+}
+
+// retryItem 表示等待重试的写盘动作，到期后由 worker 在自身协程中执行，等待期间不阻塞其他路径。
+type retryItem struct {
+	tasks   []StoreTask // 动作涉及的任务，动作最终成功或失败后统一汇报结果
+	action  func() error
+	err     error
+	attempt int
+	backoff time.Duration
+	due     time.Time
+	held    []StoreTask // 等待期间到达的同路径任务，重试结束后按顺序处理
+}
+
+// worker 表示独占一部分 StorePath 的写盘协程，同一文件只会被一个 worker 写入。
+type worker struct {
+	pool    *Pool
+	taskCh  chan []StoreTask
+	files   map[string]*openFile
+	retries map[string]*retryItem
+}
+
+// newWorker 创建 worker。
+func newWorker(pool *Pool, queueSize int) *worker {
+	return &worker{
+		pool:    pool,
+		taskCh:  make(chan []StoreTask, queueSize),
+		files:   make(map[string]*openFile),
+		retries: make(map[string]*retryItem),
+	}
+}
+
+// run 消费任务队列，队列暂时为空时统一刷新缓冲，定期关闭空闲句柄并执行到期的重试。
+// 队列关闭后关闭全部句柄，等待中的重试全部结束后退出。
+func (w *worker) run() {
+	defer w.pool.wg.Done()
+
+	ticker := time.NewTicker(w.pool.config.IdleTimeout / 2)
+	defer ticker.Stop()
+	retryTimer := time.NewTimer(time.Hour)
+	retryTimer.Stop()
+	defer retryTimer.Stop()
+
+	taskCh := w.taskCh
+	for {
+		if taskCh == nil {
+			w.closeAll()
+			if len(w.retries) == 0 {
+				return
+			}
+		}
+		var retryC <-chan time.Time
+		if due, ok := w.nextRetry(); ok {
+			retryTimer.Reset(time.Until(due))
+			retryC = retryTimer.C
+		}
+
+		select {
+		case tasks, ok := <-taskCh:
+			if !ok {
+				taskCh = nil
+				continue
+			}
+			w.handleTasks(tasks)
+			// 连续到达的任务合并处理，同一文件的多行只产生一次实际写入
+			if !w.drainQueue() {
+				taskCh = nil
+			}
+			w.flushAll()
+		case now := <-retryC:
+			w.runRetries(now)
+			w.flushAll()
+		case <-ticker.C:
+			w.closeIdle(time.Now())
+		}
+	}
+}
+
+// drainQueue 非阻塞地处理队列中已有的任务，队列关闭时返回 false。
+func (w *worker) drainQueue() bool {
+	for {
+		select {
+		case tasks, ok := <-w.taskCh:
+			if !ok {
+				return false
+			}
+			w.handleTasks(tasks)
+		default:
+			return true
+		}
+	}
+}
+
+// handleTasks 依次处理一批任务。
+func (w *worker) handleTasks(tasks []StoreTask) {
+	for _, task := range tasks {
+		w.processTask(task)
+	}
+}
+
+// processTask 执行单个任务的写盘动作，行任务仅写入缓冲区，由 flushAll 统一落盘并汇报结果。
+// 路径上有等待中的重试时任务暂存到重试结束，保持同一路径的写入顺序。
+func (w *worker) processTask(task StoreTask) {
+	if task.StorePath == "" {
+		w.pool.complete(task, fmt.Errorf("store path is empty"))
+		return
+	}
+	if w.holdForRetry(task) {
+		return
+	}
+	if task.Sink != nil {
+		write := func() error {
+			return task.Sink.WriteTask(task)
+		}
+		if !sinkRetryable(task.Sink) {
+			w.pool.complete(task, write())
+			return
+		}
+		w.attempt(task.StorePath, []StoreTask{task}, write)
+		return
+	}
+	if task.WriteRaw {
+		// 原始内容直接写盘，先释放该路径上的句柄避免与缓冲内容交错，
+		// 刷新失败时已缓冲的行由重试重新写入，原始内容需排在其后
+		if !w.closeFile(task.StorePath) && w.holdForRetry(task) {
+			return
+		}
+		w.attempt(task.StorePath, []StoreTask{task}, func() error {
+			if task.Atomic {
+				return utils.WriteBytesAtomic(task.StorePath, task.StoreContent)
+			}
+			return utils.WriteBytes(task.StorePath, task.StoreContent, task.Overwrite)
+		})
+		return
+	}
+
+	// 覆盖写入前释放原句柄，刷新失败时等重试写完旧内容后再覆盖，避免重试截断或追加到新内容上
+	if task.Overwrite && !w.closeFile(task.StorePath) && w.holdForRetry(task) {
+		return
+	}
+	if err := w.bufferLine(task); err != nil {
+		// 缓冲区刷新失败，已落盘部分由 dropFile 安排的重试处理，该行在其后重新写入
+		if w.holdForRetry(task) {
+			return
+		}
+		// 打开文件失败时退回到直接写盘重试
+		w.scheduleRetry(task.StorePath, []StoreTask{task}, err, func() error {
+			return utils.WriteLine(task.StorePath, string(task.StoreContent), task.Overwrite)
+		})
+	}
+}
+
+// holdForRetry 路径上有等待中的重试时暂存任务，返回任务是否已暂存。
+func (w *worker) holdForRetry(task StoreTask) bool {
+	item, ok := w.retries[task.StorePath]
+	if ok {
+		item.held = append(item.held, task)
+	}
+	return ok
+}
+
+// bufferLine 将行任务写入对应文件句柄的缓冲区，覆盖写入时调用方需先关闭原句柄。
+func (w *worker) bufferLine(task StoreTask) error {
+	of, err := w.getFile(task.StorePath, task.Overwrite)
+	if err != nil {
+		return err
+	}
+	of.lastUsed = time.Now()
+	if _, err := of.writer.Write(task.StoreContent); err != nil {
+		w.dropFile(task.StorePath, err)
+		return err
+	}
+	if err := of.writer.WriteByte('\n'); err != nil {
+		w.dropFile(task.StorePath, err)
+		return err
+	}
+	of.pending = append(of.pending, task)
+	of.buffered += int64(len(task.StoreContent)) + 1
+	return nil
+}
+
+// attempt 执行写盘动作，成功时汇报结果，失败时安排重试。
+func (w *worker) attempt(path string, tasks []StoreTask, action func() error) {
+	if err := action(); err != nil {
+		w.scheduleRetry(path, tasks, err, action)
+		return
+	}
+	for _, task := range tasks {
+		w.pool.complete(task, nil)
+	}
+}
+
+// scheduleRetry 安排失败动作在退避时长后重试，未配置重试时直接汇报失败。
+func (w *worker) scheduleRetry(path string, tasks []StoreTask, err error, action func() error) {
+	if w.pool.config.MaxRetries == 0 {
+		for _, task := range tasks {
+			w.pool.complete(task, err)
+		}
+		return
+	}
+	if item, ok := w.retries[path]; ok {
+		// 同一路径已有等待中的重试，失败的任务在其后重新处理
+		item.held = append(item.held, tasks...)
+		return
+	}
+	backoff := w.pool.config.RetryBackoff
+	w.retries[path] = &retryItem{
+		tasks:   tasks,
+		action:  action,
+		err:     err,
+		backoff: backoff,
+		due:     time.Now().Add(backoff),
+	}
+}
+
+// nextRetry 返回最早到期的重试时间。
+func (w *worker) nextRetry() (time.Time, bool) {
+	var due time.Time
+	for _, item := range w.retries {
+		if due.IsZero() || item.due.Before(due) {
+			due = item.due
+		}
+	}
+	return due, !due.IsZero()
+}
+
+// runRetries 执行到期的重试，成功或次数用尽时汇报结果并处理暂存的任务，否则按指数退避再次等待。
+func (w *worker) runRetries(now time.Time) {
+	for path, item := range w.retries {
+		if item.due.After(now) {
+			continue
+		}
+		w.pool.retryCount.Add(1)
+		item.attempt++
+		item.err = item.action()
+		if item.err != nil && item.attempt < w.pool.config.MaxRetries {
+			item.backoff = min(item.backoff*2, w.pool.config.MaxRetryBackoff)
+			item.due = now.Add(item.backoff)
+			continue
+		}
+		delete(w.retries, path)
+		for _, task := range item.tasks {
+			w.pool.complete(task, item.err)
+		}
+		w.handleTasks(item.held)
+	}
+}
+
+// getFile 获取已打开的句柄，不存在时按需打开，超出上限时关闭最久未使用的句柄。
+func (w *worker) getFile(path string, overwrite bool) (*openFile, error) {
+	if of, ok := w.files[path]; ok {
+		return of, nil
+	}
+	if len(w.files) >= w.pool.config.MaxOpenFiles {
+		w.closeOldest()
+	}
+	if err := utils.EnsureDir(path, true); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, utils.ParseFlagFromOver(overwrite), 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	of := &openFile{
+		file:     f,
+		writer:   bufio.NewWriter(f),
+		offset:   info.Size(),
+		lastUsed: time.Now(),
+	}
+	w.files[path] = of
+	w.pool.openFiles.Add(1)
+	return of, nil
+}
+
+// flushFile 将句柄中的缓冲写入磁盘并汇报其中行任务的结果。
+func (w *worker) flushFile(path string, of *openFile) bool {
+	if err := of.writer.Flush(); err != nil {
+		w.dropFile(path, err)
+		return false
+	}
+	for _, task := range of.pending {
+		w.pool.complete(task, nil)
+	}
+	of.pending = of.pending[:0]
+	of.offset += of.buffered
+	of.buffered = 0
+	return true
+}
+
+// flushAll 刷新所有句柄的缓冲。
+func (w *worker) flushAll() {
+	for path, of := range w.files {
+		w.flushFile(path, of)
+	}
+}
+
+// closeFile 刷新并关闭指定路径的句柄，刷新失败时返回 false，此时句柄已由 dropFile 丢弃。
+func (w *worker) closeFile(path string) bool {
+	of, ok := w.files[path]
+	if !ok {
+		return true
+	}
+	if !w.flushFile(path, of) {
+		return false
+	}
+	delete(w.files, path)
+	w.pool.openFiles.Add(-1)
+	if err := of.file.Close(); err != nil {
+		w.pool.logger().Errorf("close store file failed: %v, store_path=%s", err, path)
+	}
+	return true
+}
+
+// dropFile 在写入出错后丢弃句柄，并安排重试尚未确认落盘的行任务。
+// 缓冲区可能已部分写入文件，重试时先截断到最近一次成功刷新的位置，避免重复写入。
+func (w *worker) dropFile(path string, cause error) {
+	of, ok := w.files[path]
+	if !ok {
+		return
+	}
+	delete(w.files, path)
+	w.pool.openFiles.Add(-1)
+	_ = of.file.Close()
+
+	pending := of.pending
+	of.pending = nil
+	if len(pending) == 0 {
+		return
+	}
+	lines := make([]string, len(pending))
+	for i, task := range pending {
+		lines[i] = string(task.StoreContent)
+	}
+	offset := of.offset
+	w.scheduleRetry(path, pending, cause, func() error {
+		if err := os.Truncate(path, offset); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return utils.WriteLines(path, lines, false)
+	})
+}
+
+// closeIdle 关闭空闲超时的句柄。
+func (w *worker) closeIdle(now time.Time) {
+	for path, of := range w.files {
+		if now.Sub(of.lastUsed) >= w.pool.config.IdleTimeout {
+			w.closeFile(path)
+		}
+	}
+}
+
+// closeOldest 关闭最久未使用的句柄。
+func (w *worker) closeOldest() {
+	var oldestPath string
+	var oldestTime time.Time
+	for path, of := range w.files {
+		if oldestPath == "" || of.lastUsed.Before(oldestTime) {
+			oldestPath = path
+			oldestTime = of.lastUsed
+		}
+	}
+	if oldestPath != "" {
+		w.closeFile(oldestPath)
+	}
+}
+
+// closeAll 刷新并关闭所有句柄。
+func (w *worker) closeAll() {
+	for path := range w.files {
+		w.closeFile(path)
+	}
+}
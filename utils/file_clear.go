@@ -79,7 +79,7 @@ func ClearPaths(paths []string, rebuild bool, perm os.FileMode) (removed []strin
 				errMsg := fmt.Errorf("Failed to rebuild directory %s (permission: %o): %v; ", cleanPath, finalPerm, err)
 				errors = append(errors, errMsg)
 			} else {
-				fmt.Printf("Directory %s has been rebuilt (permission: %o)\n", cleanPath, finalPerm)
+				pkgLogger.Get().Infof("directory %s has been rebuilt (permission: %o)", cleanPath, finalPerm)
 			}
 		}
 	}
@@ -85,30 +85,7 @@ func detectBOM(data []byte) string {
 // 若编码名称无法识别，默认返回UTF-8编码器
 func NormalizedEncode(encodeName string) encoding.Encoding {
 	// 第一步：统一编码名称格式（规范化）
-	normalizedName := strings.ToLower(encodeName)
-	switch normalizedName {
-	case "utf8", "utf-8":
-		normalizedName = "utf-8"
-	case "utf16", "utf-16":
-		normalizedName = "utf-16"
-	case "utf16le", "utf-16le":
-		normalizedName = "utf-16le"
-	case "utf16be", "utf-16be":
-		normalizedName = "utf-16be"
-	case "utf32", "utf-32":
-		normalizedName = "utf-32"
-	case "utf32le", "utf-32le":
-		normalizedName = "utf-32le"
-	case "utf32be", "utf-32be":
-		normalizedName = "utf-32be"
-	case "windows-936", "gbk": // windows-936是GBK的Windows编码名
-		normalizedName = "gbk"
-	case "gbk2312", "gb2312": // 处理常见的GB2312别名
-		normalizedName = "gb2312"
-	case "big5-hkscs", "big5": // big5-hkscs是Big5的扩展
-		normalizedName = "big5"
-		// 其他未匹配的编码名称保持原样，后续判断
-	}
+	normalizedName := NormalizedEncodeName(encodeName)
 
 	// 第二步：根据规范化后的名称映射到对应的编码器
 	switch normalizedName {
@@ -138,6 +115,38 @@ func NormalizedEncode(encodeName string) encoding.Encoding {
 	}
 }
 
+// NormalizedEncodeName 统一编码名称格式，如"utf8"转换为"utf-8"，"windows-936"转换为"gbk"，未匹配的名称转换为小写后返回
+func NormalizedEncodeName(encodeName string) string {
+	normalizedName := strings.ToLower(strings.TrimSpace(encodeName))
+	switch normalizedName {
+	case "utf8", "utf-8":
+		normalizedName = "utf-8"
+	case "utf16", "utf-16":
+		normalizedName = "utf-16"
+	case "utf16le", "utf-16le":
+		normalizedName = "utf-16le"
+	case "utf16be", "utf-16be":
+		normalizedName = "utf-16be"
+	case "utf32", "utf-32":
+		normalizedName = "utf-32"
+	case "utf32le", "utf-32le":
+		normalizedName = "utf-32le"
+	case "utf32be", "utf-32be":
+		normalizedName = "utf-32be"
+	case "windows-936", "gbk": // windows-936是GBK的Windows编码名
+		normalizedName = "gbk"
+	case "gbk2312", "gb2312": // 处理常见的GB2312别名
+		normalizedName = "gb2312"
+	case "gb-18030", "gb18030": // chardet 检测结果为 GB-18030
+		normalizedName = "gb18030"
+	case "big5-hkscs", "big5": // big5-hkscs是Big5的扩展
+		normalizedName = "big5"
+		// 其他未匹配的编码名称保持原样，后续判断
+	}
+	return normalizedName
+}
+
+// DetectFileEncode 返回文件编码，encode 为空时根据 BOM 和文件内容自动检测
 func DetectFileEncode(filePath string, encode string) string {
 	// 如果未指定编码，则自动检测
 	if encode == "" {
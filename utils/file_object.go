@@ -2,7 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"github.com/winezer0/xutils/logging"
 	"os"
 	"time"
 )
@@ -36,7 +35,7 @@ func PathToFileInfo(path string) (FileInfo, error) {
 	// 检测文件编码（保持原逻辑）
 	encoding, err := detectFileEncoding(path)
 	if err != nil {
-		logging.Warnf("detect file %s encoding error:%v", path, err)
+		pkgLogger.Get().Warnf("detect file %s encoding error:%v", path, err)
 	}
 
 	// 组装并返回FileInfo
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 // SaveToFile 写入文件内容，如果目录不存在会自动创建
@@ -116,3 +117,39 @@ func WriteBytes(path string, data []byte, overwrite bool) error {
 	err = f.Close()
 	return err
 }
+
+// WriteBytesAtomic 先写入同目录下的临时文件，再通过重命名替换目标文件，读取方不会看到写了一半的内容
+func WriteBytesAtomic(path string, data []byte) error {
+	if err := EnsureDir(path, true); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("create temp file error: %w", err)
+	}
+	tmpName := tmpFile.Name()
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("write temp file error: %w", err)
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("sync temp file error: %w", err)
+	}
+	if err = tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("close temp file error: %w", err)
+	}
+	if err = os.Chmod(tmpName, 0644); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("chmod temp file error: %w", err)
+	}
+	if err = os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("replace file error: %w", err)
+	}
+	return nil
+}
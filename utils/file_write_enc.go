@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// EncodeBOM 返回指定编码的 BOM 字节，GBK 等没有 BOM 的编码返回 nil
+func EncodeBOM(encode string) []byte {
+	switch NormalizedEncodeName(encode) {
+	case "", "utf-8":
+		return []byte{0xEF, 0xBB, 0xBF}
+	case "utf-16", "utf-16be":
+		return []byte{0xFE, 0xFF}
+	case "utf-16le":
+		return []byte{0xFF, 0xFE}
+	case "utf-32", "utf-32be":
+		return []byte{0x00, 0x00, 0xFE, 0xFF}
+	case "utf-32le":
+		return []byte{0xFF, 0xFE, 0x00, 0x00}
+	default:
+		return nil
+	}
+}
+
+// CheckEncode 校验写入编码名称能否识别，空串表示 utf-8，无法识别时返回错误，避免静默按 utf-8 写入
+func CheckEncode(encode string) error {
+	switch NormalizedEncodeName(encode) {
+	case "", "utf-8":
+		return nil
+	}
+	if NormalizedEncode(encode) == unicode.UTF8 {
+		return fmt.Errorf("unsupported encoding: %s", encode)
+	}
+	return nil
+}
+
+// nopWriteCloser 为不需要刷新的写入器提供空的 Close
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// NewEncodeWriter 返回将 UTF-8 内容转换为指定编码后写入 w 的写入器，encode 为空或无法识别时原样写入。
+// Close 刷新尚未转换的内容，不会关闭 w
+func NewEncodeWriter(w io.Writer, encode string) io.WriteCloser {
+	enc := NormalizedEncode(encode)
+	if enc == unicode.UTF8 {
+		return nopWriteCloser{Writer: w}
+	}
+	return transform.NewWriter(w, enc.NewEncoder())
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "github.com/winezer0/xutils/liblog"
+
+// pkgLogger 文件与配置工具函数使用的日志器
+var pkgLogger liblog.Holder
+
+// SetLogger 设置 utils 的调试与提示输出，如加载 YAML 的过程、编码检测失败和目录重建，默认不输出。
+func SetLogger(l liblog.Logger) {
+	pkgLogger.Set(l)
+}
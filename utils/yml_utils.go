@@ -2,7 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"github.com/winezer0/xutils/logging"
 	"os"
 
 	"gopkg.in/yaml.v3"
@@ -18,12 +17,12 @@ func LoadYAMLBytes(content []byte, v interface{}) error {
 
 // LoadYAML 从文件加载YAML数据
 func LoadYAML(filePath string, v interface{}) error {
-	logging.Debugf("Loading YAML file...: %s", filePath)
+	pkgLogger.Get().Debugf("loading YAML file: %s", filePath)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read the YAML file: %v", err)
 	}
-	logging.Debugf("Size of the YAML file: %d byte", len(data))
+	pkgLogger.Get().Debugf("size of the YAML file: %d byte", len(data))
 	if err := LoadYAMLBytes(data, v); err != nil {
 		return fmt.Errorf("failed to parse YAML data: %v", err)
 	}